@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hojooneum/pm/internal/cli"
+	"github.com/hojooneum/pm/internal/fs"
+	"github.com/hojooneum/pm/internal/manual"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkStatusFlag bool
+	checkResetFlag  bool
+	checkRunFlag    string
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check [section]",
+	Short: "Work through a section's checklist",
+	Long: `Walk the task-list items ("- [ ] ...") of a section one by one, ticking
+each off as it is done. Answer y when an item is done, s to leave it for later
+or q to stop.
+
+Progress is saved in .pm/.checklists/ after every item, not in the section, so
+the markdown stays a clean template and the checklist can be picked up again
+later, from another terminal. Items already ticked in the markdown ("- [x]")
+count as done.
+
+Each time through the checklist is a run of its own. Running pm check again
+continues the latest run with the items still open, and once that run is
+complete the next pm check starts a new one, named by its start time. Use
+--run to name a run, e.g. after the release it is for, or to go back to an
+earlier one.
+
+Use --status to see what is done in the run, by whom and when, and --reset to
+clear the run's progress.`,
+	Args:              checkArgs(cobra.MaximumNArgs(1)),
+	ValidArgsFunction: completeSection,
+	RunE:              runCheck,
+}
+
+func init() {
+	checkCmd.Flags().BoolVar(&checkStatusFlag, "status", false, "show progress through the checklist")
+	checkCmd.Flags().BoolVar(&checkResetFlag, "reset", false, "clear the run's saved progress")
+	checkCmd.Flags().StringVar(&checkRunFlag, "run", "", "run to work on, started if new (default: the latest, or a new one once it is complete)")
+	rootCmd.AddCommand(checkCmd)
+}
+
+// Answers to the per-item prompt, in the order of checkChoices.
+const (
+	checkDone = iota
+	checkSkip
+	checkQuit
+)
+
+var checkChoices = []cli.Choice{
+	{Key: "y", Label: "done"},
+	{Key: "s", Label: "skip"},
+	{Key: "q", Label: "quit"},
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	if checkStatusFlag && checkResetFlag {
+		return &usageError{fmt.Errorf("--status and --reset cannot be combined (see '%s --help')", cmd.CommandPath())}
+	}
+	walk := !checkStatusFlag && !checkResetFlag
+	if walk && !isInteractive() {
+		return &usageError{fmt.Errorf("ticking items off needs a terminal; use --status to see progress (see '%s --help')", cmd.CommandPath())}
+	}
+	if checkRunFlag != "" {
+		if err := fs.ValidateChecklistRun(checkRunFlag); err != nil {
+			return &usageError{err}
+		}
+	}
+
+	layers, err := projectLayers()
+	if err != nil {
+		return err
+	}
+	entry, ok, err := resolveSection(cmd, layers, args)
+	if !ok {
+		return err
+	}
+	s, err := loadSection(entry)
+	if err != nil {
+		return err
+	}
+	tasks := manual.ParseTasks(s.Body)
+	if len(tasks) == 0 {
+		return fmt.Errorf("%s/%s has no task-list items (\"- [ ] ...\")", s.Group, s.Name)
+	}
+
+	section := entry.Group + "/" + entry.Name
+	c, err := currentChecklist(cmd, layers[0], section, tasks, walk)
+	if err != nil {
+		return err
+	}
+	if checkResetFlag {
+		reset, err := fs.ResetChecklist(layers[0], section, c.Run)
+		if err != nil {
+			return err
+		}
+		if reset {
+			fmt.Fprintf(w, "Reset run %s of the checklist for %s.\n", c.Run, section)
+		} else {
+			fmt.Fprintf(w, "No progress saved for %s.\n", section)
+		}
+		return nil
+	}
+	if checkStatusFlag {
+		out := cli.NewChecklistOutput(tasks, c)
+		if structuredOutput() {
+			return cli.Render(w, outputFormat, out)
+		}
+		cli.PrintChecklist(w, out)
+		return nil
+	}
+	return walkChecklist(cmd, layers[0], tasks, c)
+}
+
+// currentChecklist returns the run of the section's checklist to work on: the
+// one named by --run, or else the latest. Walking a checklist whose latest
+// run is complete starts a new run instead. With no run saved, the checklist
+// returned has no run name unless it is to be walked.
+func currentChecklist(cmd *cobra.Command, l fs.Layer, section string, tasks []manual.Task, walk bool) (fs.Checklist, error) {
+	if checkRunFlag != "" {
+		return fs.ReadChecklist(l, section, checkRunFlag)
+	}
+	c, ok, err := fs.LatestChecklist(l, section)
+	if err != nil {
+		return c, err
+	}
+	if !walk {
+		if !ok {
+			c = fs.NewChecklist(section, "")
+		}
+		return c, nil
+	}
+	if ok {
+		out := cli.NewChecklistOutput(tasks, c)
+		if out.Done < out.Total {
+			return c, nil
+		}
+	}
+
+	next := fs.NewChecklist(section, fs.ChecklistRun(time.Now()))
+	if ok {
+		fmt.Fprintf(cmd.OutOrStdout(), "Run %s is complete; starting run %s.\n", c.Run, next.Run)
+	}
+	return next, nil
+}
+
+// walkChecklist asks about each open item in turn, saving the checklist
+// after every item ticked.
+func walkChecklist(cmd *cobra.Command, l fs.Layer, tasks []manual.Task, c fs.Checklist) error {
+	w := cmd.OutOrStdout()
+	scanner := bufio.NewScanner(os.Stdin)
+
+	out := cli.NewChecklistOutput(tasks, c)
+	fmt.Fprintf(w, "%s: %s\n", cli.ChecklistName(out), cli.ChecklistProgress(out))
+
+	var path []string
+walk:
+	for i, t := range tasks {
+		if out.Items[i].Done {
+			continue
+		}
+		if !slices.Equal(t.Path, path) {
+			path = t.Path
+			fmt.Fprintln(w)
+			if len(path) > 0 {
+				fmt.Fprintln(w, strings.Join(path, " > "))
+			}
+		}
+		fmt.Fprintln(w, "  "+cli.ChecklistItem(out.Items[i]))
+
+		choice, err := cli.Choose(scanner, w, "  Done?", checkChoices, checkDone)
+		if errors.Is(err, io.EOF) {
+			choice, err = checkQuit, nil
+		}
+		if err != nil {
+			return err
+		}
+		switch choice {
+		case checkDone:
+			now := time.Now()
+			if c.Started.IsZero() {
+				c.Started = now
+			}
+			c.Done[t.Key] = fs.ChecklistMark{Time: now, User: currentUser()}
+			if c, err = fs.WriteChecklist(l, c); err != nil {
+				return err
+			}
+			// Items ticked meanwhile in another pm check are done too.
+			out = cli.NewChecklistOutput(tasks, c)
+		case checkQuit:
+			break walk
+		}
+	}
+
+	out = cli.NewChecklistOutput(tasks, c)
+	fmt.Fprintln(w)
+	if out.Done == out.Total {
+		fmt.Fprintf(w, "Checklist complete: %s.\n", cli.ChecklistProgress(out))
+	} else {
+		fmt.Fprintf(w, "%s. Run 'pm check %s --run %s' to continue.\n", cli.ChecklistProgress(out), c.Section, c.Run)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hojooneum/pm/internal/fs"
+	"github.com/hojooneum/pm/internal/manual"
+	"github.com/spf13/cobra"
+)
+
+// The functions below back dynamic shell completion. Cobra's built-in
+// "pm completion <shell>" command generates the scripts that call them.
+// They never fail loudly: without a manual they simply offer nothing.
+
+// completeSection offers section names and their group/name forms, with titles
+// as descriptions, for the first argument of open, edit and toc. After a "#"
+// it offers the section's heading slugs instead.
+func completeSection(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	if name, _, ok := strings.Cut(toComplete, "#"); ok {
+		return completeHeading(name, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+	sections, ok := completionSections()
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names, qualified []string
+	for _, s := range sections {
+		title := s.Title
+		if title == "" {
+			title = s.Name
+		}
+		names = append(names, s.Name+"\t"+title)
+		qualified = append(qualified, s.Group+"/"+s.Name+"\t"+title)
+	}
+	return withPrefix(append(names, qualified...), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeHeading offers "section#slug" completions for a section's headings.
+func completeHeading(name, toComplete string) []string {
+	layers, err := projectLayers()
+	if err != nil {
+		return nil
+	}
+	entry, err := fs.FindSection(layers, name)
+	if err != nil {
+		return nil
+	}
+	s, err := loadSection(entry)
+	if err != nil {
+		return nil
+	}
+
+	var out []string
+	for _, h := range manual.ParseHeadings(s.Body) {
+		out = append(out, name+"#"+h.Slug+"\t"+h.Text)
+	}
+	return withPrefix(out, toComplete)
+}
+
+// completeGroup offers the groups of all layers for pm list.
+func completeGroup(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	layers, err := projectLayers()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	seen := make(map[string]bool)
+	var groups []string
+	for _, l := range layers {
+		gs, err := l.Groups()
+		if err != nil {
+			continue
+		}
+		for _, g := range gs {
+			if !seen[g] {
+				seen[g] = true
+				groups = append(groups, g)
+			}
+		}
+	}
+	return withPrefix(groups, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTag offers the tags in use, with their section counts, for --tag.
+func completeTag(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	sections, ok := completionSections()
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var tags []string
+	for _, t := range manual.CountTags(sections) {
+		tags = append(tags, t.Tag+"\t"+sectionCount(t.Count))
+	}
+	return withPrefix(tags, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTemplate offers the built-in presets and the .json files and
+// directories matching what has been typed so far for init --template.
+func completeTemplate(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var out []string
+	for _, p := range manual.ListPresets() {
+		out = append(out, p.Name+"\t"+p.Description)
+	}
+	out = withPrefix(out, toComplete)
+
+	directive := cobra.ShellCompDirectiveNoFileComp
+	matches, _ := filepath.Glob(toComplete + "*")
+	sort.Strings(matches)
+	for _, m := range matches {
+		info, err := os.Stat(m)
+		switch {
+		case err != nil:
+			continue
+		case info.IsDir():
+			out = append(out, m+string(filepath.Separator))
+			directive |= cobra.ShellCompDirectiveNoSpace
+		case strings.EqualFold(filepath.Ext(m), ".json"):
+			out = append(out, m+"\ttemplate file")
+		}
+	}
+	return out, directive
+}
+
+// completeSectionTemplate offers the built-in section templates for pm new --from-template.
+func completeSectionTemplate(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var out []string
+	for name, raw := range manual.DefaultTemplates {
+		desc := name
+		if s, err := manual.ParseSection(name, "", raw); err == nil && s.Title != "" {
+			desc = s.Title
+		}
+		out = append(out, name+"\t"+desc)
+	}
+	sort.Strings(out)
+	return withPrefix(out, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeLayer offers the names of the layers in the merged manual for --layer.
+func completeLayer(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	layers, err := projectLayers()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, len(layers))
+	for i, l := range layers {
+		names[i] = l.Name
+	}
+	return withPrefix(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSearch completes --tag values for pm search. Since search parses its
+// own flags, cobra hands every argument to this function.
+func completeSearch(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 && args[len(args)-1] == "--tag" {
+		return completeTag(cmd, args, toComplete)
+	}
+	if value, ok := strings.CutPrefix(toComplete, "--tag="); ok {
+		tags, directive := completeTag(cmd, args, value)
+		for i, t := range tags {
+			tags[i] = "--tag=" + t
+		}
+		return tags, directive
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionSections loads the merged manual, skipping sections that fail to parse.
+func completionSections() ([]manual.Section, bool) {
+	layers, err := projectLayers()
+	if err != nil {
+		return nil, false
+	}
+	entries, err := fs.ListEntries(layers)
+	if err != nil {
+		return nil, false
+	}
+
+	var sections []manual.Section
+	for _, e := range entries {
+		if s, err := loadSection(e); err == nil {
+			sections = append(sections, s)
+		}
+	}
+	return sections, true
+}
+
+// withPrefix keeps the completions (value, optionally followed by a tab and a
+// description) whose value starts with prefix.
+func withPrefix(completions []string, prefix string) []string {
+	var out []string
+	for _, c := range completions {
+		if strings.HasPrefix(c, prefix) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// sectionCount formats a number of sections for completion descriptions.
+func sectionCount(n int) string {
+	if n == 1 {
+		return "1 section"
+	}
+	return fmt.Sprintf("%d sections", n)
+}
@@ -1,21 +1,21 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
 
-	"github.com/hojooneum/pm/internal/cli"
-	"github.com/hojooneum/pm/internal/fs"
 	"github.com/spf13/cobra"
 )
 
 var editCmd = &cobra.Command{
-	Use:   "edit <section>",
+	Use:   "edit [section]",
 	Short: "Open a section in $EDITOR for editing",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runEdit,
+	Long: `Open a section in $EDITOR for editing.
+
+Without a section name, pick one interactively with a fuzzy finder.`,
+	Args:              checkArgs(cobra.MaximumNArgs(1)),
+	ValidArgsFunction: completeSection,
+	RunE:              runEdit,
 }
 
 func init() {
@@ -23,25 +23,21 @@ func init() {
 }
 
 func runEdit(cmd *cobra.Command, args []string) error {
-	root, _ := os.Getwd()
-	w := cmd.OutOrStdout()
-
-	if !fs.DetectPMDir(root) {
-		cli.PrintNoPMDir(w)
-		return nil
+	layers, err := projectLayers()
+	if err != nil {
+		return err
 	}
 
-	_, relPath, err := fs.FindSection(root, args[0])
-	if err != nil {
-		fmt.Fprintf(w, "Error: %v\n\n", err)
-		fmt.Fprintln(w, "Available sections:")
-		sections, _ := loadAllSections(root)
-		cli.PrintSectionList(w, sections)
-		return nil
+	entry, ok, err := resolveSection(cmd, layers, args)
+	if !ok {
+		return err
 	}
 
-	absPath := filepath.Join(fs.PMPath(root), relPath)
+	return openInEditor(cmd, entry.Path())
+}
 
+// openInEditor opens a file in $EDITOR, falling back to $VISUAL and then vi.
+func openInEditor(cmd *cobra.Command, path string) error {
 	editor := os.Getenv("EDITOR")
 	if editor == "" {
 		editor = os.Getenv("VISUAL")
@@ -50,7 +46,7 @@ func runEdit(cmd *cobra.Command, args []string) error {
 		editor = "vi"
 	}
 
-	c := exec.Command(editor, absPath)
+	c := exec.Command(editor, path)
 	c.Stdin = cmd.InOrStdin()
 	c.Stdout = cmd.OutOrStdout()
 	c.Stderr = cmd.ErrOrStderr()
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hojooneum/pm/internal/fs"
+	"github.com/hojooneum/pm/internal/manual"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes returned by pm. Scripts may rely on these values.
+const (
+	ExitOK        = 0 // success
+	ExitError     = 1 // any failure not listed below
+	ExitUsage     = 2 // invalid command, flag or argument
+	ExitNoManual  = 3 // no .pm/ directory found
+	ExitNotFound  = 4 // section, heading, group or session not found
+	ExitAmbiguous = 5 // section name matches several sections
+)
+
+// usageError marks errors caused by invalid command-line input.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string { return e.err.Error() }
+func (e *usageError) Unwrap() error { return e.err }
+
+// ExitCode maps an error returned by Execute to the process exit code.
+func ExitCode(err error) int {
+	var (
+		usage     *usageError
+		notFound  *fs.SectionNotFoundError
+		noHeading *manual.HeadingNotFoundError
+		noGroup   *fs.GroupNotFoundError
+		noSession *fs.SessionNotFoundError
+		ambiguous *fs.AmbiguousSectionError
+	)
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &usage):
+		return ExitUsage
+	case errors.Is(err, fs.ErrNoManual):
+		return ExitNoManual
+	case errors.As(err, &notFound), errors.As(err, &noHeading), errors.As(err, &noGroup), errors.As(err, &noSession):
+		return ExitNotFound
+	case errors.As(err, &ambiguous):
+		return ExitAmbiguous
+	default:
+		return ExitError
+	}
+}
+
+// checkArgs wraps a cobra argument validator so its failures are reported as usage errors.
+func checkArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := validate(cmd, args); err != nil {
+			return &usageError{fmt.Errorf("%w (see '%s --help')", err, cmd.CommandPath())}
+		}
+		return nil
+	}
+}
+
+// flagError reports flag parsing failures as usage errors.
+func flagError(cmd *cobra.Command, err error) error {
+	return &usageError{fmt.Errorf("%w (see '%s --help')", err, cmd.CommandPath())}
+}
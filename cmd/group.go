@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hojooneum/pm/internal/fs"
+	"github.com/hojooneum/pm/internal/manual"
+	"github.com/spf13/cobra"
+)
+
+var (
+	groupLayerFlag       string
+	groupTitleFlag       string
+	groupDescriptionFlag string
+	groupWeightFlag      int
+	groupIconFlag        string
+	groupYesFlag         bool
+)
+
+var groupCmd = &cobra.Command{
+	Use:   "group",
+	Short: "Create, remove and rename groups",
+	Long: `Create, remove and rename groups of sections.
+
+A group is a directory under .pm/. It may hold a _group.yaml with a display
+title, description, icon and sort weight, which pm list uses for its headings
+and to order groups (lower weights first; groups default to 0):
+
+  title: Platform runbooks
+  description: What to do when the pager goes off
+  weight: -10
+  icon: 🚒`,
+}
+
+var groupAddCmd = &cobra.Command{
+	Use:   "add <group>",
+	Short: "Create a group",
+	Long: `Create an empty group in the nearest .pm/, writing a _group.yaml when
+--title, --description, --weight or --icon is given.
+
+  pm group add platform --title "Platform runbooks" --weight -10`,
+	Args:              checkArgs(cobra.ExactArgs(1)),
+	ValidArgsFunction: cobra.NoFileCompletions,
+	RunE:              runGroupAdd,
+}
+
+var groupRmCmd = &cobra.Command{
+	Use:   "rm <group>",
+	Short: "Delete a group and its sections",
+	Long: `Delete a group, its sections and its _group.yaml. Links to the deleted
+sections from other sections are turned into plain text, as with pm rm.
+
+pm asks for confirmation first; use --yes to skip it in scripts.`,
+	Args:              checkArgs(cobra.ExactArgs(1)),
+	ValidArgsFunction: completeGroup,
+	RunE:              runGroupRm,
+}
+
+var groupRenameCmd = &cobra.Command{
+	Use:   "rename <group> <new-name>",
+	Short: "Rename a group",
+	Long: `Rename a group, moving its sections and _group.yaml and updating links to
+them, as with pm mv. To change only the title shown by pm list, set title in
+the group's _group.yaml instead.`,
+	Args:              checkArgs(cobra.ExactArgs(2)),
+	ValidArgsFunction: completeGroup,
+	RunE:              runGroupRename,
+}
+
+func init() {
+	groupAddCmd.Flags().StringVar(&groupTitleFlag, "title", "", "title shown by pm list (default: \"<Group> sections\")")
+	groupAddCmd.Flags().StringVar(&groupDescriptionFlag, "description", "", "one-line group description")
+	groupAddCmd.Flags().IntVar(&groupWeightFlag, "weight", 0, "sort weight; lower weights are listed first")
+	groupAddCmd.Flags().StringVar(&groupIconFlag, "icon", "", "icon shown before the title, e.g. an emoji")
+	groupRmCmd.Flags().BoolVarP(&groupYesFlag, "yes", "y", false, "do not ask for confirmation")
+
+	groupCmd.PersistentFlags().StringVar(&groupLayerFlag, "layer", "", "change groups in a specific layer (default: the nearest .pm/)")
+	_ = groupCmd.RegisterFlagCompletionFunc("layer", completeLayer)
+
+	groupCmd.AddCommand(groupAddCmd, groupRmCmd, groupRenameCmd)
+	rootCmd.AddCommand(groupCmd)
+}
+
+// groupLayer returns the layer named by --layer, or the nearest one.
+func groupLayer() (fs.Layer, error) {
+	layers, err := projectLayers()
+	if err != nil {
+		return fs.Layer{}, err
+	}
+	if groupLayerFlag == "" {
+		return layers[0], nil
+	}
+	l, err := fs.SelectLayer(layers, groupLayerFlag)
+	if err != nil {
+		return fs.Layer{}, err
+	}
+	if l.Global {
+		return fs.Layer{}, &usageError{fmt.Errorf("the global manual has no groups")}
+	}
+	return l, nil
+}
+
+func runGroupAdd(cmd *cobra.Command, args []string) error {
+	group := args[0]
+	if err := manual.ValidateGroupName(group); err != nil {
+		return &usageError{err}
+	}
+
+	l, err := groupLayer()
+	if err != nil {
+		return err
+	}
+	meta := fs.GroupMeta{
+		Title:       groupTitleFlag,
+		Description: groupDescriptionFlag,
+		Weight:      groupWeightFlag,
+		Icon:        groupIconFlag,
+	}
+	if err := fs.CreateGroup(l, group, meta); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Created group %s\n", group)
+	return nil
+}
+
+func runGroupRm(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	group := args[0]
+
+	l, err := groupLayer()
+	if err != nil {
+		return err
+	}
+	entries, err := fs.GroupEntries(l, group)
+	if err != nil {
+		return err
+	}
+
+	if !groupYesFlag {
+		prompt := fmt.Sprintf("Delete group %s?", group)
+		if len(entries) > 0 {
+			prompt = fmt.Sprintf("Delete group %s and its %d section(s)?", group, len(entries))
+		}
+		ok, err := confirmAction(cmd, prompt)
+		if err != nil || !ok {
+			return err
+		}
+	}
+
+	updated, err := fs.RemoveGroup(l, group)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Removed group %s\n", group)
+	printUpdatedLinks(w, updated)
+	return nil
+}
+
+func runGroupRename(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	group, newGroup := args[0], args[1]
+	if err := manual.ValidateGroupName(newGroup); err != nil {
+		return &usageError{err}
+	}
+
+	l, err := groupLayer()
+	if err != nil {
+		return err
+	}
+	updated, err := fs.RenameGroup(l, group, newGroup)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Renamed group %s -> %s\n", group, newGroup)
+	printUpdatedLinks(w, updated)
+	return nil
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 	"io"
-	"os"
 	"path/filepath"
 
 	"github.com/hojooneum/pm/internal/cli"
@@ -27,6 +26,7 @@ var initCmd = &cobra.Command{
 func init() {
 	initCmd.Flags().StringVar(&templateFlag, "template", "", "template preset name or path to JSON template file")
 	initCmd.Flags().BoolVar(&listTemplatesFlag, "list-templates", false, "list available template presets")
+	_ = initCmd.RegisterFlagCompletionFunc("template", completeTemplate)
 	rootCmd.AddCommand(initCmd)
 }
 
@@ -34,6 +34,9 @@ func runInit(cmd *cobra.Command, args []string) error {
 	w := cmd.OutOrStdout()
 
 	if listTemplatesFlag {
+		if structuredOutput() {
+			return cli.Render(w, outputFormat, cli.NewTemplateOutput(manual.ListPresets()))
+		}
 		cli.PrintTemplateList(w, manual.ListPresets())
 		return nil
 	}
@@ -43,8 +46,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	root, _ := os.Getwd()
-	return doInit(w, root, tmpl)
+	return doInit(w, initRoot(), tmpl)
 }
 
 // doInit scaffolds the .pm/ directory using the given template.
@@ -1,62 +1,62 @@
 package cmd
 
 import (
-	"os"
-
 	"github.com/hojooneum/pm/internal/cli"
-	"github.com/hojooneum/pm/internal/fs"
 	"github.com/hojooneum/pm/internal/manual"
 	"github.com/spf13/cobra"
 )
 
+var (
+	listFieldFlags  []string
+	listTagFlags    []string
+	listAnyTagsFlag bool
+)
+
 var listCmd = &cobra.Command{
-	Use:     "list [core|custom]",
-	Aliases: []string{"ls"},
-	Short:   "List available sections",
-	Args:    cobra.MaximumNArgs(1),
-	RunE:    runList,
+	Use:               "list [core|custom]",
+	Aliases:           []string{"ls"},
+	Short:             "List available sections",
+	Args:              checkArgs(cobra.MaximumNArgs(1)),
+	ValidArgsFunction: completeGroup,
+	RunE:              runList,
 }
 
 func init() {
+	listCmd.Flags().StringSliceVar(&listFieldFlags, "field", nil, "also show a frontmatter field, e.g. owner (repeatable)")
+	addTagFlags(listCmd, &listTagFlags, &listAnyTagsFlag)
 	rootCmd.AddCommand(listCmd)
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	root, _ := os.Getwd()
 	w := cmd.OutOrStdout()
 
-	if !fs.DetectPMDir(root) {
-		cli.PrintNoPMDir(w)
-		return nil
+	layers, err := projectLayers()
+	if err != nil {
+		return err
 	}
 
-	var groups []string
-	if len(args) == 1 {
-		groups = []string{args[0]}
-	} else {
-		var err error
-		groups, err = fs.ListGroups(root)
-		if err != nil {
-			return err
-		}
+	sections, err := loadAllSections(cmd.ErrOrStderr(), layers)
+	if err != nil {
+		return err
 	}
 
-	var sections []manual.Section
-	for _, group := range groups {
-		names, err := fs.ListMarkdownFiles(root, group)
-		if err != nil {
-			return err
-		}
-		for _, name := range names {
-			raw, err := fs.ReadFile(root, group+"/"+name+".md")
-			if err != nil {
-				return err
+	if len(args) == 1 {
+		var filtered []manual.Section
+		for _, s := range sections {
+			if s.Group == args[0] {
+				filtered = append(filtered, s)
 			}
-			s := manual.ParseSection(name, group, raw)
-			sections = append(sections, s)
 		}
+		sections = filtered
+	}
+
+	sections = manual.FilterByTags(sections, listTagFlags, listAnyTagsFlag)
+
+	if structuredOutput() {
+		return cli.Render(w, outputFormat, cli.NewSectionListOutput(sections))
 	}
 
-	cli.PrintSectionList(w, sections)
+	groups := groupMetas(cmd.ErrOrStderr(), layers)
+	cli.PrintSectionList(w, sections, groups, listFieldFlags...)
 	return nil
 }
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hojooneum/pm/internal/fs"
+	"github.com/hojooneum/pm/internal/manual"
+	"github.com/spf13/cobra"
+)
+
+var mvLayerFlag string
+
+var mvCmd = &cobra.Command{
+	Use:   "mv <section> <group>",
+	Short: "Move a section to another group",
+	Long: `Move a section to another group of the same manual.
+
+Relative links to the section from other sections, and the section's own
+relative links, are updated. Inside a git work tree the file is moved with
+git mv so its history follows it.
+
+  pm mv vpn infra`,
+	Args:              checkArgs(cobra.ExactArgs(2)),
+	ValidArgsFunction: completeMv,
+	RunE:              runMv,
+}
+
+func init() {
+	mvCmd.Flags().StringVar(&mvLayerFlag, "layer", "", "move the section in a specific layer (e.g. root)")
+	_ = mvCmd.RegisterFlagCompletionFunc("layer", completeLayer)
+	rootCmd.AddCommand(mvCmd)
+}
+
+func runMv(cmd *cobra.Command, args []string) error {
+	group := args[1]
+	if err := manual.ValidateGroupName(group); err != nil {
+		return &usageError{err}
+	}
+
+	entry, err := findExactSection(mvLayerFlag, args[0])
+	if err != nil {
+		return err
+	}
+	if entry.Layer.Global {
+		return &usageError{fmt.Errorf("sections in the global manual have no groups; use 'pm rename' instead")}
+	}
+	return moveSection(cmd, entry, group, entry.Name)
+}
+
+// moveSection moves or renames a section and reports what changed.
+func moveSection(cmd *cobra.Command, entry fs.Entry, group, name string) error {
+	w := cmd.OutOrStdout()
+
+	to, updated, err := fs.MoveSection(entry, group, name)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Moved %s/%s -> %s/%s\n", entry.Group, entry.Name, to.Group, to.Name)
+	printUpdatedLinks(w, updated)
+	return nil
+}
+
+// completeMv completes the section, then the target group.
+func completeMv(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 1 {
+		return completeGroup(cmd, nil, toComplete)
+	}
+	return completeSection(cmd, args, toComplete)
+}
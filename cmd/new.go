@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hojooneum/pm/internal/cli"
+	"github.com/hojooneum/pm/internal/fs"
+	"github.com/hojooneum/pm/internal/manual"
+	"github.com/spf13/cobra"
+)
+
+var (
+	newGroupFlag       string
+	newTitleFlag       string
+	newDescriptionFlag string
+	newTagFlags        []string
+	newTemplateFlag    string
+	newEditFlag        bool
+)
+
+var newCmd = &cobra.Command{
+	Use:   "new <name>",
+	Short: "Create a new section",
+	Long: `Create a new section in the nearest .pm/ with YAML frontmatter.
+
+The name becomes the filename and must use lowercase letters, digits and
+hyphens. Sections are created in the custom group unless --group is given.
+An existing section with the same name in any group is never overwritten.
+
+  pm new db-failover --title "Database Failover" --tag database --tag incident
+  pm new api-incident --from-template troubleshoot --edit`,
+	Args:              checkArgs(cobra.ExactArgs(1)),
+	ValidArgsFunction: cobra.NoFileCompletions,
+	RunE:              runNew,
+}
+
+func init() {
+	newCmd.Flags().StringVar(&newGroupFlag, "group", "custom", "group to create the section in")
+	newCmd.Flags().StringVar(&newTitleFlag, "title", "", "section title (default: derived from the name)")
+	newCmd.Flags().StringVar(&newDescriptionFlag, "description", "", "one-line section description")
+	newCmd.Flags().StringSliceVar(&newTagFlags, "tag", nil, "tag the section (repeatable)")
+	newCmd.Flags().StringVar(&newTemplateFlag, "from-template", "", "start from a built-in section template, e.g. troubleshoot")
+	newCmd.Flags().BoolVar(&newEditFlag, "edit", false, "open the new section in $EDITOR")
+	_ = newCmd.RegisterFlagCompletionFunc("group", completeGroup)
+	_ = newCmd.RegisterFlagCompletionFunc("tag", completeTag)
+	_ = newCmd.RegisterFlagCompletionFunc("from-template", completeSectionTemplate)
+	rootCmd.AddCommand(newCmd)
+}
+
+func runNew(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+	name := args[0]
+
+	if err := manual.ValidateSectionName(name); err != nil {
+		return &usageError{err}
+	}
+	if err := manual.ValidateGroupName(newGroupFlag); err != nil {
+		return &usageError{err}
+	}
+
+	layers, err := projectLayers()
+	if err != nil {
+		return err
+	}
+
+	def := manual.SectionDef{
+		Name:        name,
+		Group:       newGroupFlag,
+		Title:       newTitleFlag,
+		Description: newDescriptionFlag,
+		Tags:        newTagFlags,
+	}
+	// A name that has a built-in template (e.g. "deploy") starts from it, as in pm init.
+	template := newTemplateFlag
+	if _, ok := manual.DefaultTemplates[name]; ok && template == "" {
+		template = name
+	}
+
+	if def.Title == "" && template != name {
+		def.Title = titleFromName(name)
+	}
+
+	var content string
+	if template != "" {
+		if content, err = manual.GenerateSectionContentFrom(def, template); err != nil {
+			return &usageError{err}
+		}
+	} else {
+		content = manual.GenerateSectionContent(def)
+	}
+
+	// New sections go into the nearest project manual; a same-named section
+	// further up is shadowed, which is how a service overrides a runbook.
+	entry, err := fs.CreateSection(layers[0], def.Group, name, content)
+	if err != nil {
+		return err
+	}
+
+	if structuredOutput() {
+		s, err := loadSection(entry)
+		if err != nil {
+			return err
+		}
+		if err := cli.Render(w, outputFormat, cli.NewSectionOutput(s, false)); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(w, "Created %s\n", entry.Path())
+	}
+
+	if newEditFlag {
+		return openInEditor(cmd, entry.Path())
+	}
+	return nil
+}
+
+// titleFromName turns a section name into a title: "db-failover" becomes "Db Failover".
+func titleFromName(name string) string {
+	words := strings.Split(name, "-")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/hojooneum/pm/internal/cli"
 	"github.com/hojooneum/pm/internal/fs"
@@ -10,41 +13,140 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	openLayerFlag   string
+	openHeadingFlag string
+	openRawFlag     bool
+	noPagerFlag     bool
+	openSetFlags    []string
+)
+
 var openCmd = &cobra.Command{
-	Use:   "open <section>",
+	Use:   "open [section[#heading]]",
 	Short: "Open and display a section",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runOpen,
+	Long: `Open and display a section.
+
+Without a section name, pick one interactively with a fuzzy finder that
+filters by name, title, tags and description and previews the body.
+
+Append "#heading" (or use --heading) to print only one heading and its
+subsections. Headings are named by GitHub-style slugs; see 'pm toc'.
+
+  pm open deploy#rollback
+  pm open deploy --heading "Rollback Procedure"
+
+The markdown is rendered for the terminal, wrapped to its width and, unless
+NO_COLOR is set or output is redirected, styled with color. Use --raw for the
+markdown source.
+
+Parameters declared in the section's frontmatter are substituted for their
+<name>, $NAME and ${NAME} placeholders. Give values with --set name=value;
+parameters without a default are prompted for, or reported as missing when
+not running interactively. Secret parameters are left as placeholders.
+
+Output taller than the terminal is shown in a pager: $PM_PAGER, the "pager"
+setting in .pm/config.yaml or ~/.config/pm/config.yaml, $PAGER, or less -R.
+Use --no-pager, or "pager: false" in config.yaml, to turn it off.`,
+	Args:              checkArgs(cobra.MaximumNArgs(1)),
+	ValidArgsFunction: completeSection,
+	RunE:              runOpen,
 }
 
 func init() {
+	openCmd.Flags().StringVar(&openHeadingFlag, "heading", "", "print only this heading (slug or text) and its subsections")
+	openCmd.Flags().BoolVar(&openRawFlag, "raw", false, "print the markdown source instead of rendering it")
+	openCmd.Flags().BoolVar(&noPagerFlag, "no-pager", false, "do not pipe output through a pager")
+	addSetFlag(openCmd, &openSetFlags)
+	openCmd.Flags().StringVar(&openLayerFlag, "layer", "", "open the section from a specific layer (e.g. root, global)")
+	_ = openCmd.RegisterFlagCompletionFunc("layer", completeLayer)
 	rootCmd.AddCommand(openCmd)
 }
 
 func runOpen(cmd *cobra.Command, args []string) error {
-	root, _ := os.Getwd()
 	w := cmd.OutOrStdout()
 
-	if !fs.DetectPMDir(root) {
-		cli.PrintNoPMDir(w)
-		return nil
+	args, slug := splitHeading(args)
+	given, err := parseSets(cmd, openSetFlags)
+	if err != nil {
+		return err
+	}
+	if openHeadingFlag != "" {
+		if slug != "" {
+			return &usageError{fmt.Errorf("use either section#heading or --heading, not both (see '%s --help')", cmd.CommandPath())}
+		}
+		slug = openHeadingFlag
 	}
 
-	group, relPath, err := fs.FindSection(root, args[0])
+	layers, err := projectLayers()
 	if err != nil {
-		fmt.Fprintf(w, "Error: %v\n\n", err)
-		fmt.Fprintln(w, "Available sections:")
-		sections, _ := loadAllSections(root)
-		cli.PrintSectionList(w, sections)
-		return nil
+		return err
+	}
+	search := layers
+	if openLayerFlag != "" {
+		l, err := fs.SelectLayer(layers, openLayerFlag)
+		if err != nil {
+			return err
+		}
+		search = []fs.Layer{l}
 	}
 
-	raw, err := fs.ReadFile(root, relPath)
+	entry, ok, err := resolveSection(cmd, search, args)
+	if !ok {
+		return err
+	}
+
+	s, err := loadSection(entry)
 	if err != nil {
 		return err
 	}
+	if slug != "" {
+		h, body, err := manual.ExtractHeading(s.Body, slug)
+		if err != nil {
+			return fmt.Errorf("%s/%s: %w", s.Group, s.Name, err)
+		}
+		s.Body, slug = body, h.Slug
+	}
 
-	s := manual.ParseSection(args[0], group, raw)
-	cli.PrintSectionContent(w, s)
-	return nil
+	// Secrets are only substituted by pm run, so they never end up on screen.
+	params := publicParams(manual.UsedParams(s.Body, s.Params))
+	values, err := resolveParams(cmd, bufio.NewScanner(os.Stdin), s, params, given, isInteractive())
+	if err != nil {
+		return err
+	}
+	s.Body = manual.Substitute(s.Body, params, values)
+
+	if structuredOutput() {
+		out := cli.NewSectionOutput(s, true)
+		out.Heading = slug
+		return cli.Render(w, outputFormat, out)
+	}
+
+	var buf bytes.Buffer
+	if openRawFlag {
+		cli.PrintSectionContent(&buf, s)
+	} else {
+		cli.PrintSectionRendered(&buf, s, cli.MarkdownOptions{
+			Width: terminalWidth(w),
+			Color: colorEnabled(w),
+		})
+	}
+
+	if noPagerFlag {
+		_, err := w.Write(buf.Bytes())
+		return err
+	}
+	cfg, err := fs.LoadConfig(layers)
+	if err != nil {
+		return err
+	}
+	return writePaged(w, buf.Bytes(), cfg)
+}
+
+// splitHeading separates a "section#heading" argument into the section and the heading slug.
+func splitHeading(args []string) ([]string, string) {
+	if len(args) == 0 {
+		return args, ""
+	}
+	name, slug, _ := strings.Cut(args[0], "#")
+	return []string{name}, slug
 }
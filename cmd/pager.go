@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/hojooneum/pm/internal/cli"
+	"github.com/hojooneum/pm/internal/fs"
+	"golang.org/x/term"
+)
+
+// PagerEnv names the environment variable that overrides the pager for pm
+// only, like GIT_PAGER does for git.
+const PagerEnv = "PM_PAGER"
+
+const defaultPager = "less -R"
+
+// pagerCommand returns the pager to use, or "" for none. In order of
+// precedence: $PM_PAGER, the pager setting in config.yaml, $PAGER, less -R.
+// An empty value or "cat" turns paging off.
+func pagerCommand(cfg fs.Config) string {
+	pager := defaultPager
+	if env, ok := os.LookupEnv(PagerEnv); ok {
+		pager = env
+	} else if cfg.PagerDisabled {
+		pager = ""
+	} else if cfg.Pager != "" {
+		pager = cfg.Pager
+	} else if env, ok := os.LookupEnv("PAGER"); ok {
+		pager = env
+	}
+
+	if pager = strings.TrimSpace(pager); pager == "cat" {
+		return ""
+	}
+	return pager
+}
+
+// writePaged writes out to w, through the pager when w is a terminal and out
+// is taller than the window. The pager is run by sh, as git and man do, so
+// it may carry quoted arguments. If it cannot be started or is not found,
+// out is written directly.
+func writePaged(w io.Writer, out []byte, cfg fs.Config) error {
+	if f, ok := terminalFile(w); ok {
+		if pager := pagerCommand(cfg); pager != "" {
+			width, height, err := term.GetSize(int(f.Fd()))
+			if err == nil && height > 0 && cli.DisplayLines(string(out), width) >= height {
+				c := exec.Command("sh", "-c", pager)
+				c.Stdin = bytes.NewReader(out)
+				c.Stdout = f
+				c.Stderr = os.Stderr
+				if err := c.Start(); err == nil {
+					// The pager reports its own errors; sh exits 127 when
+					// the command is not found.
+					_ = c.Wait()
+					if c.ProcessState.ExitCode() != 127 {
+						return nil
+					}
+				}
+			}
+		}
+	}
+	_, err := w.Write(out)
+	return err
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hojooneum/pm/internal/cli"
+	"github.com/hojooneum/pm/internal/manual"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// secretMask is shown in place of secret parameter values.
+const secretMask = "********"
+
+// addSetFlag registers --set for commands that substitute runbook parameters.
+func addSetFlag(cmd *cobra.Command, sets *[]string) {
+	cmd.Flags().StringArrayVar(sets, "set", nil, "set a runbook parameter, e.g. --set namespace=prod (repeatable)")
+}
+
+// parseSets parses --set key=value flags.
+func parseSets(cmd *cobra.Command, sets []string) (map[string]string, error) {
+	given := make(map[string]string)
+	for _, kv := range sets {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || k == "" {
+			return nil, &usageError{fmt.Errorf("invalid --set %q: expected key=value (see '%s --help')", kv, cmd.CommandPath())}
+		}
+		given[k] = v
+	}
+	return given, nil
+}
+
+// resolveParams returns values for the given parameters from --set, their
+// defaults and, when interactive is true, prompts on stderr for the rest.
+// Otherwise missing required parameters are reported together, before the
+// caller prints or runs anything.
+func resolveParams(cmd *cobra.Command, scanner *bufio.Scanner, s manual.Section, params []manual.Param, given map[string]string, interactive bool) (map[string]string, error) {
+	if err := s.CheckParams(given); err != nil {
+		return nil, &usageError{err}
+	}
+
+	values, missing := manual.ResolveParams(params, given)
+	if len(missing) == 0 {
+		return values, nil
+	}
+	if !interactive {
+		names := make([]string, len(missing))
+		for i, p := range missing {
+			names[i] = p.Name
+		}
+		return nil, &usageError{&manual.MissingParamsError{Names: names}}
+	}
+
+	w := cmd.ErrOrStderr()
+	for _, p := range missing {
+		v, err := promptParam(scanner, w, p)
+		if errors.Is(err, io.EOF) {
+			return nil, &usageError{&manual.MissingParamsError{Names: []string{p.Name}}}
+		}
+		if err != nil {
+			return nil, err
+		}
+		values[p.Name] = v
+	}
+	fmt.Fprintln(w)
+	return values, nil
+}
+
+// promptParam asks for one parameter's value: a choice among its allowed
+// values, a hidden entry for secrets, or a line of text.
+func promptParam(scanner *bufio.Scanner, w io.Writer, p manual.Param) (string, error) {
+	label := p.Name
+	if p.Description != "" {
+		label += " (" + p.Description + ")"
+	}
+
+	switch {
+	case len(p.Values) > 0:
+		idx, err := cli.SelectOption(scanner, w, "Select "+label+":", p.Values, make([]string, len(p.Values)), 0)
+		if err != nil {
+			return "", err
+		}
+		return p.Values[idx], nil
+	case p.Secret:
+		fmt.Fprintf(w, "%s: ", label)
+		secret, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(w)
+		if err != nil {
+			return "", err
+		}
+		if len(secret) == 0 {
+			return "", io.EOF
+		}
+		return string(secret), nil
+	default:
+		return cli.PromptValue(scanner, w, label)
+	}
+}
+
+// maskSecrets returns a copy of values with secret parameters masked, for display.
+func maskSecrets(params []manual.Param, values map[string]string) map[string]string {
+	masked := make(map[string]string, len(values))
+	for k, v := range values {
+		masked[k] = v
+	}
+	for _, p := range params {
+		if _, ok := masked[p.Name]; ok && p.Secret {
+			masked[p.Name] = secretMask
+		}
+	}
+	return masked
+}
+
+// publicParams returns the parameters that are not secret.
+func publicParams(params []manual.Param) []manual.Param {
+	var out []manual.Param
+	for _, p := range params {
+		if !p.Secret {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// paramEnv returns the parameters as NAME=value environment variables, which
+// is how steps get their values (see paramRefs).
+func paramEnv(params []manual.Param, values map[string]string) []string {
+	var env []string
+	for _, p := range params {
+		if v, ok := values[p.Name]; ok {
+			env = append(env, p.EnvName()+"="+v)
+		}
+	}
+	return env
+}
+
+// paramRefs maps each parameter to a reference to its environment variable,
+// "${NAME}", to substitute for <name> in a step before it runs. Values then
+// reach the shell through paramEnv rather than as code, so they are neither
+// interpreted by it nor visible in the process list.
+func paramRefs(params []manual.Param) map[string]string {
+	refs := make(map[string]string, len(params))
+	for _, p := range params {
+		refs[p.Name] = "${" + p.EnvName() + "}"
+	}
+	return refs
+}
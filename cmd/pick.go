@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/hojooneum/pm/internal/cli"
+	"github.com/hojooneum/pm/internal/fs"
+	"github.com/hojooneum/pm/internal/manual"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// resolveSection finds the section named by args, or lets the user pick one
+// when args is empty. ok is false when the user cancels the picker.
+func resolveSection(cmd *cobra.Command, layers []fs.Layer, args []string) (entry fs.Entry, ok bool, err error) {
+	if len(args) > 0 {
+		entry, err = fs.FindSection(layers, args[0])
+		return entry, err == nil, err
+	}
+	if !isInteractive() {
+		return fs.Entry{}, false, &usageError{fmt.Errorf("requires a section name when not running interactively (see '%s --help')", cmd.CommandPath())}
+	}
+
+	entries, err := fs.ListEntries(layers)
+	if err != nil {
+		return fs.Entry{}, false, err
+	}
+	if len(entries) == 0 {
+		return fs.Entry{}, false, errors.New("the manual has no sections")
+	}
+	entries, sections, err := loadEntries(cmd.ErrOrStderr(), entries)
+	if err != nil {
+		return fs.Entry{}, false, err
+	}
+
+	idx, err := pickSection(cmd, sections)
+	if errors.Is(err, cli.ErrCancelled) {
+		return fs.Entry{}, false, nil
+	}
+	if err != nil {
+		return fs.Entry{}, false, err
+	}
+	return entries[idx], true, nil
+}
+
+// pickSection shows the fuzzy finder on stderr, so the chosen section can still
+// be piped from stdout. Terminals that cannot draw it (TERM unset or "dumb", or
+// stderr redirected) get the numbered prompt instead.
+func pickSection(cmd *cobra.Command, sections []manual.Section) (int, error) {
+	if t := os.Getenv("TERM"); t != "" && t != "dumb" && term.IsTerminal(int(os.Stderr.Fd())) {
+		return cli.PickSection(os.Stdin, os.Stderr, sections)
+	}
+
+	options := make([]string, len(sections))
+	descriptions := make([]string, len(sections))
+	for i, s := range sections {
+		options[i] = s.Group + "/" + s.Name
+		descriptions[i] = s.Title
+	}
+	return cli.SelectOption(bufio.NewScanner(os.Stdin), cmd.ErrOrStderr(), "Select a section:", options, descriptions, 0)
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/user"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hojooneum/pm/internal/fs"
+	"github.com/hojooneum/pm/internal/manual"
+	"github.com/spf13/cobra"
+)
+
+// maxSessionOutput is how much of a step's output a session log keeps: the
+// end of the output, where errors usually are.
+const maxSessionOutput = 16 << 10
+
+// sessionRecorder writes the session log of a pm run. A log that cannot be
+// written never stops a run: the problem is reported once on stderr and
+// recording stops. A nil recorder records nothing.
+type sessionRecorder struct {
+	log     *fs.SessionLog
+	errw    io.Writer
+	secrets []string
+	out     *outputTail // output of the step being run
+}
+
+// startSession creates a session log in the layer and records start.
+func startSession(cmd *cobra.Command, l fs.Layer, start fs.SessionEvent, secrets []string) *sessionRecorder {
+	start.Type = fs.EventStart
+	start.Time = time.Now()
+	start.Host, _ = os.Hostname()
+	start.User = currentUser()
+
+	r := &sessionRecorder{errw: cmd.ErrOrStderr(), secrets: secrets}
+	log, err := fs.CreateSession(l, start)
+	if err != nil {
+		r.fail(err)
+		return nil
+	}
+	r.log = log
+	return r
+}
+
+// capture returns a writer collecting the output of the next step, or nil
+// when not recording.
+func (r *sessionRecorder) capture() io.Writer {
+	if r == nil {
+		return nil
+	}
+	r.out = &outputTail{}
+	return r.out
+}
+
+// step records a step with secrets redacted from its command, which may have
+// been edited to contain them. For a step that was run, exit is its failure,
+// if any, and the output collected since capture is recorded, redacted too.
+func (r *sessionRecorder) step(e fs.SessionEvent, exit *exec.ExitError) {
+	if r == nil {
+		return
+	}
+	e.Type = fs.EventStep
+	e.Command = r.redact(e.Command)
+	if e.Action == fs.ActionSkip {
+		e.Time = time.Now()
+	} else {
+		end := time.Now()
+		code := 0
+		if exit != nil {
+			code = exit.ExitCode()
+		}
+		e.End, e.ExitCode = &end, &code
+		if r.out != nil {
+			e.Output, e.Truncated = r.redact(r.out.String()), r.out.truncated
+		}
+	}
+	r.out = nil
+	r.append(e)
+}
+
+// finish records how the run ended and closes the log.
+func (r *sessionRecorder) finish(status string) {
+	if r == nil {
+		return
+	}
+	r.append(fs.SessionEvent{Type: fs.EventEnd, Time: time.Now(), Status: status})
+	if r.log != nil {
+		r.log.Close()
+		fmt.Fprintf(r.errw, "Recorded session %s (see 'pm sessions show %s')\n", r.log.ID, r.log.ID)
+	}
+}
+
+func (r *sessionRecorder) append(e fs.SessionEvent) {
+	if r.log == nil {
+		return
+	}
+	if err := r.log.Append(e); err != nil {
+		r.fail(err)
+		r.log.Close()
+		r.log = nil
+	}
+}
+
+func (r *sessionRecorder) fail(err error) {
+	fmt.Fprintf(r.errw, "Warning: not recording this session: %v\n", err)
+}
+
+// redact replaces secret parameter values in s.
+func (r *sessionRecorder) redact(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, secretMask)
+	}
+	return s
+}
+
+// currentUser returns the name of the user running pm, or "" if unknown.
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+// secretValues returns the non-empty values of secret parameters.
+func secretValues(params []manual.Param, values map[string]string) []string {
+	var secrets []string
+	for _, p := range params {
+		if v := values[p.Name]; p.Secret && v != "" {
+			secrets = append(secrets, v)
+		}
+	}
+	return secrets
+}
+
+// outputTail keeps the last maxSessionOutput bytes written to it. It is
+// safe for concurrent use, as a command's stdout and stderr are copied by
+// separate goroutines.
+type outputTail struct {
+	mu        sync.Mutex
+	buf       []byte
+	truncated bool
+}
+
+func (t *outputTail) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buf = append(t.buf, p...)
+	if over := len(t.buf) - maxSessionOutput; over > 0 {
+		t.buf = append(t.buf[:0], t.buf[over:]...)
+		t.truncated = true
+	}
+	return len(p), nil
+}
+
+func (t *outputTail) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return string(t.buf)
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hojooneum/pm/internal/fs"
+	"github.com/spf13/cobra"
+)
+
+func TestSessionRecorder_RedactsCommand(t *testing.T) {
+	l := fs.Layer{Name: fs.RootLayer, Root: t.TempDir()}
+	cmd := &cobra.Command{}
+	cmd.SetErr(io.Discard)
+	rec := startSession(cmd, l, fs.SessionEvent{Section: "core/rotate"}, []string{"s3cr3t"})
+	if rec == nil {
+		t.Fatal("expected a recorder")
+	}
+	rec.step(fs.SessionEvent{Step: 1, Total: 1, Action: fs.ActionRun, Command: `curl -H "Authorization: s3cr3t"`, Edited: true}, nil)
+	rec.finish(fs.StatusCompleted)
+
+	_, events, err := fs.ReadSession(l, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range events {
+		if strings.Contains(e.Command, "s3cr3t") {
+			t.Errorf("secret recorded in command %q", e.Command)
+		}
+		if e.Type == fs.EventStep && e.Command != `curl -H "Authorization: `+secretMask+`"` {
+			t.Errorf("got command %q", e.Command)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/hojooneum/pm/internal/manual"
+	"github.com/spf13/cobra"
+)
+
+var renameLayerFlag string
+
+var renameCmd = &cobra.Command{
+	Use:   "rename <section> <new-name>",
+	Short: "Rename a section",
+	Long: `Rename a section, keeping it in its group.
+
+Relative links to the section from other sections are updated. Inside a git
+work tree the file is renamed with git mv so its history follows it.
+
+  pm rename vpn vpn-access`,
+	Args:              checkArgs(cobra.ExactArgs(2)),
+	ValidArgsFunction: completeRename,
+	RunE:              runRename,
+}
+
+func init() {
+	renameCmd.Flags().StringVar(&renameLayerFlag, "layer", "", "rename the section in a specific layer (e.g. root, global)")
+	_ = renameCmd.RegisterFlagCompletionFunc("layer", completeLayer)
+	rootCmd.AddCommand(renameCmd)
+}
+
+func runRename(cmd *cobra.Command, args []string) error {
+	name := args[1]
+	if err := manual.ValidateSectionName(name); err != nil {
+		return &usageError{err}
+	}
+
+	entry, err := findExactSection(renameLayerFlag, args[0])
+	if err != nil {
+		return err
+	}
+	return moveSection(cmd, entry, entry.Group, name)
+}
+
+// completeRename completes only the section; the new name is free text.
+func completeRename(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeSection(cmd, args, toComplete)
+}
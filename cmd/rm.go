@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hojooneum/pm/internal/cli"
+	"github.com/hojooneum/pm/internal/fs"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rmYesFlag   bool
+	rmLayerFlag string
+)
+
+var rmCmd = &cobra.Command{
+	Use:   "rm <section>",
+	Short: "Delete a section",
+	Long: `Delete a section and turn links to it from other sections into plain text.
+
+The section must be named exactly (name or group/name). pm asks for
+confirmation first; use --yes to skip it in scripts. Inside a git work tree
+the file is removed with git rm.`,
+	Args:              checkArgs(cobra.ExactArgs(1)),
+	ValidArgsFunction: completeSection,
+	RunE:              runRm,
+}
+
+func init() {
+	rmCmd.Flags().BoolVarP(&rmYesFlag, "yes", "y", false, "do not ask for confirmation")
+	rmCmd.Flags().StringVar(&rmLayerFlag, "layer", "", "delete the section from a specific layer (e.g. root, global)")
+	_ = rmCmd.RegisterFlagCompletionFunc("layer", completeLayer)
+	rootCmd.AddCommand(rmCmd)
+}
+
+func runRm(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	entry, err := findExactSection(rmLayerFlag, args[0])
+	if err != nil {
+		return err
+	}
+	linking, err := fs.LinksTo(entry)
+	if err != nil {
+		return err
+	}
+
+	if !rmYesFlag {
+		prompt := fmt.Sprintf("Delete %s?", entry.Path())
+		if len(linking) > 0 {
+			prompt = fmt.Sprintf("Delete %s and unlink it from %d section(s)?", entry.Path(), len(linking))
+		}
+		ok, err := confirmAction(cmd, prompt)
+		if err != nil || !ok {
+			return err
+		}
+	}
+
+	updated, err := fs.RemoveSection(entry)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Removed %s\n", entry.Path())
+	printUpdatedLinks(w, updated)
+	return nil
+}
+
+// findExactSection resolves a section for commands that change files. Unlike
+// open, the name must match a file exactly: a fuzzy or alias match is reported
+// as not found, with the match offered as a suggestion.
+func findExactSection(layerFlag, name string) (fs.Entry, error) {
+	layers, err := projectLayers()
+	if err != nil {
+		return fs.Entry{}, err
+	}
+	if layerFlag != "" {
+		l, err := fs.SelectLayer(layers, layerFlag)
+		if err != nil {
+			return fs.Entry{}, err
+		}
+		layers = []fs.Layer{l}
+	}
+
+	entry, err := fs.FindSection(layers, name)
+	if err != nil {
+		return fs.Entry{}, err
+	}
+	base := name[strings.LastIndex(name, "/")+1:]
+	if !strings.EqualFold(entry.Name, base) {
+		return fs.Entry{}, fmt.Errorf("%w; did you mean %s/%s?", &fs.SectionNotFoundError{Name: name}, entry.Group, entry.Name)
+	}
+	return entry, nil
+}
+
+// confirmAction asks the user to confirm a change, defaulting to no. Without a
+// terminal to ask on it fails with a usage error pointing at --yes.
+func confirmAction(cmd *cobra.Command, prompt string) (bool, error) {
+	if !isInteractive() {
+		return false, &usageError{fmt.Errorf("refusing to continue without confirmation; pass --yes (see '%s --help')", cmd.CommandPath())}
+	}
+	ok, err := cli.ConfirmYesNo(bufio.NewScanner(os.Stdin), cmd.OutOrStdout(), prompt, false)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		fmt.Fprintln(cmd.OutOrStdout(), "Aborted.")
+	}
+	return ok, nil
+}
+
+// printUpdatedLinks lists the sections whose links were rewritten.
+func printUpdatedLinks(w io.Writer, updated []fs.Entry) {
+	if len(updated) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "Updated links in %d section(s):\n", len(updated))
+	for _, e := range updated {
+		fmt.Fprintf(w, "  %s/%s\n", e.Group, e.Name)
+	}
+}
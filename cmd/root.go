@@ -2,61 +2,116 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/hojooneum/pm/internal/cli"
 	"github.com/hojooneum/pm/internal/fs"
 	"github.com/hojooneum/pm/internal/manual"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
-var version = "dev"
+var (
+	version    = "dev"
+	rootFlag   string
+	outputFlag string
+
+	// outputFormat is the parsed --output flag, set before any command runs.
+	outputFormat = cli.FormatText
+)
 
 var rootCmd = &cobra.Command{
 	Use:     "pm",
 	Short:   "Project manual — manage and browse runbooks from .pm/",
 	Long:    "pm is a CLI tool for managing project-specific runbooks and manuals stored in .pm/ directories.",
 	Version: version,
+	Args:    checkArgs(cobra.NoArgs),
 	RunE:    runRoot,
+
+	// Errors are printed to stderr by Execute; usage is only shown on request.
+	SilenceErrors: true,
+	SilenceUsage:  true,
+
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return applyOutputFlag()
+	},
 }
 
 func init() {
 	rootCmd.SetVersionTemplate("pm version {{.Version}}\n")
+	rootCmd.SetFlagErrorFunc(flagError)
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", "text", "output format for list, open, search, tags, sessions, check --status and init --list-templates: text, json or yaml")
+	rootCmd.PersistentFlags().StringVar(&rootFlag, "root", "", "project directory containing .pm/ (default: search upward, or $"+fs.RootEnv+")")
+	_ = rootCmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions([]string{"text", "json", "yaml"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = rootCmd.MarkPersistentFlagDirname("root")
 }
 
 func runRoot(cmd *cobra.Command, args []string) error {
-	root, _ := os.Getwd()
+	root, found := projectRoot()
+
+	if !found {
+		if !isInteractive() {
+			return fs.ErrNoManual
+		}
 
-	if !fs.DetectPMDir(root) {
 		w := cmd.OutOrStdout()
-		fmt.Fprintln(w, "No .pm/ directory found in the current directory.")
+		fmt.Fprintln(w, "No .pm/ directory found in the current directory or its parents.")
 		fmt.Fprintln(w)
+		return runInteractiveInit(cmd, initRoot())
+	}
 
-		if isInteractive() {
-			return runInteractiveInit(cmd, root)
-		}
-
-		fmt.Fprintln(w, "Run 'pm init' to create one.")
-		return nil
+	layers, err := fs.DiscoverLayers(root)
+	if err != nil {
+		return err
 	}
 
-	sections, err := loadAllSections(root)
+	sections, err := loadAllSections(cmd.ErrOrStderr(), layers)
 	if err != nil {
 		return err
 	}
 
-	cli.PrintProjectSummary(cmd.OutOrStdout(), sections)
+	groups := groupMetas(cmd.ErrOrStderr(), layers)
+
+	cli.PrintProjectSummary(cmd.OutOrStdout(), sections, groups)
 	return nil
 }
 
+// projectRoot resolves the project root from --root, $PM_ROOT, or by walking
+// up from the working directory. found is false when no .pm/ was located.
+func projectRoot() (root string, found bool) {
+	wd, _ := os.Getwd()
+	return fs.ResolveRoot(rootFlag, wd)
+}
+
+// projectLayers resolves the project root and returns its .pm/ layers, nearest first.
+// It returns fs.ErrNoManual when no .pm/ directory can be found.
+func projectLayers() ([]fs.Layer, error) {
+	root, found := projectRoot()
+	if !found {
+		return nil, fs.ErrNoManual
+	}
+	return fs.DiscoverLayers(root)
+}
+
+// initRoot returns the directory where a new .pm/ should be created:
+// the explicit --root or $PM_ROOT if set, otherwise the working directory.
+func initRoot() string {
+	if rootFlag != "" {
+		return rootFlag
+	}
+	if env := os.Getenv(fs.RootEnv); env != "" {
+		return env
+	}
+	wd, _ := os.Getwd()
+	return wd
+}
+
 // isInteractive returns true when stdin is a terminal (not piped/redirected).
 func isInteractive() bool {
-	fi, err := os.Stdin.Stat()
-	if err != nil {
-		return false
-	}
-	return fi.Mode()&os.ModeCharDevice != 0
+	return term.IsTerminal(int(os.Stdin.Fd()))
 }
 
 // runInteractiveInit drives the interactive init flow: confirm, pick template, scaffold.
@@ -92,33 +147,87 @@ func runInteractiveInit(cmd *cobra.Command, root string) error {
 	return doInit(w, root, presets[idx])
 }
 
-// loadAllSections reads and parses all sections from all groups under .pm/.
-func loadAllSections(root string) ([]manual.Section, error) {
-	groups, err := fs.ListGroups(root)
+// applyOutputFlag parses --output into outputFormat.
+func applyOutputFlag() error {
+	f, err := cli.ParseFormat(outputFlag)
+	if err != nil {
+		return &usageError{err}
+	}
+	outputFormat = f
+	return nil
+}
+
+// structuredOutput reports whether --output asks for JSON or YAML.
+func structuredOutput() bool {
+	return outputFormat != cli.FormatText
+}
+
+// loadAllSections reads and parses all sections from all layers.
+// Sections in nearer layers shadow same-named sections further up.
+// Sections with malformed frontmatter are skipped (see loadEntries).
+func loadAllSections(w io.Writer, layers []fs.Layer) ([]manual.Section, error) {
+	entries, err := fs.ListEntries(layers)
 	if err != nil {
 		return nil, err
 	}
+	_, sections, err := loadEntries(w, entries)
+	return sections, err
+}
 
-	var sections []manual.Section
-	for _, group := range groups {
-		names, err := fs.ListMarkdownFiles(root, group)
-		if err != nil {
-			return nil, err
+// loadEntries reads and parses the sections entries point to, returning the
+// entries that parsed alongside their sections. A section with malformed
+// frontmatter is skipped with a warning on w, so one bad file does not hide
+// the rest of the manual; opening it still reports the error.
+func loadEntries(w io.Writer, entries []fs.Entry) ([]fs.Entry, []manual.Section, error) {
+	kept := make([]fs.Entry, 0, len(entries))
+	sections := make([]manual.Section, 0, len(entries))
+	for _, e := range entries {
+		s, err := loadSection(e)
+		var frontmatter *manual.FrontmatterError
+		if errors.As(err, &frontmatter) {
+			fmt.Fprintf(w, "Warning: skipping %v\n", err)
+			continue
 		}
-		for _, name := range names {
-			raw, err := fs.ReadFile(root, group+"/"+name+".md")
-			if err != nil {
-				return nil, err
-			}
-			s := manual.ParseSection(name, group, raw)
-			sections = append(sections, s)
+		if err != nil {
+			return nil, nil, err
 		}
+		kept = append(kept, e)
+		sections = append(sections, s)
 	}
+	return kept, sections, nil
+}
 
-	return sections, nil
+// groupMetas returns the group metadata of the layers for display, warning on
+// w about any _group.yaml that had to be ignored.
+func groupMetas(w io.Writer, layers []fs.Layer) map[string]fs.GroupMeta {
+	metas, problems := fs.GroupMetas(layers)
+	for _, err := range problems {
+		fmt.Fprintf(w, "Warning: ignoring %v\n", err)
+	}
+	return metas
 }
 
-// Execute runs the root command.
+// loadSection reads and parses the section an entry points to.
+func loadSection(e fs.Entry) (manual.Section, error) {
+	raw, err := e.Read()
+	if err != nil {
+		return manual.Section{}, err
+	}
+	s, err := manual.ParseSection(e.Name, e.Group, raw)
+	if err != nil {
+		return manual.Section{}, fmt.Errorf("%s: %w", e.Path(), err)
+	}
+	s.Layer = e.Layer.Name
+	return s, nil
+}
+
+// Execute runs the root command. Failures are reported on stderr, keeping
+// stdout clean for the command's output; use ExitCode to map the returned
+// error to the process exit status.
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	if err != nil {
+		cli.PrintError(rootCmd.ErrOrStderr(), err)
+	}
+	return err
 }
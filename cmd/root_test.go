@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hojooneum/pm/internal/fs"
+)
+
+func TestLoadAllSections_BadFrontmatter(t *testing.T) {
+	t.Setenv(fs.GlobalEnv, filepath.Join(t.TempDir(), "no-global"))
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"core/deploy.md":  "---\ntitle: Deploy\n---\n# Deploy\n",
+		"core/legacy.md":  "---\ntitle: Fix: restart db\n---\n# Legacy\n",
+		"core/broken.md":  "---\ntitle: a: b\ntags: [db]\n---\n# Broken\n",
+		"custom/notes.md": "# Notes\n",
+	} {
+		path := filepath.Join(fs.PMPath(root), name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	layers, err := fs.DiscoverLayers(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var warnings bytes.Buffer
+	sections, err := loadAllSections(&warnings, layers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, s := range sections {
+		names = append(names, s.Group+"/"+s.Name)
+	}
+	if got := strings.Join(names, " "); got != "core/deploy core/legacy custom/notes" {
+		t.Errorf("got sections %s, want the broken one skipped", got)
+	}
+	if got := warnings.String(); !strings.HasPrefix(got, "Warning: skipping ") || !strings.Contains(got, "broken.md: frontmatter line 2:") || !strings.Contains(got, "quote values") {
+		t.Errorf("unexpected warning %q", got)
+	}
+
+	entries, err := fs.ListEntries(layers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if _, err := loadSection(e); (err != nil) != (e.Name == "broken") {
+			t.Errorf("loadSection(%s): got error %v", e.Name, err)
+		}
+	}
+}
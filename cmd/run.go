@@ -0,0 +1,499 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hojooneum/pm/internal/cli"
+	"github.com/hojooneum/pm/internal/fs"
+	"github.com/hojooneum/pm/internal/manual"
+	"github.com/spf13/cobra"
+)
+
+var (
+	runYesFlag    bool
+	runSetFlags   []string
+	runNoLogFlag  bool
+	runLogOutput  bool
+	runDryRunFlag bool
+	runEnvFlag    string
+	runForceFlag  bool
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run [section[#heading]]",
+	Short: "Step through a section's shell code blocks",
+	Long: `Step through the fenced shell code blocks (bash, sh, shell, zsh) of a
+section, or of one heading with section#heading, in order.
+
+Each block is shown before it runs; answer y to run it, s to skip it, e to
+edit it in $EDITOR first (the section itself is not changed) or a to abort.
+Blocks run with "sh -e" (or bash/zsh for those languages) in the directory
+containing .pm/, so a failing command stops the block, and output is streamed
+as it is produced. The run stops at the first block that exits non-zero
+unless the block is annotated as allowed to fail:
+
+  ` + "```bash {allow-fail}" + `
+  curl -f https://staging.example.com/health
+  ` + "```" + `
+
+Dangerous blocks can be guarded. A destructive block asks again before it
+runs, defaulting to no; a confirm block makes you type its phrase, which may
+use parameters. Declining either aborts the run. An env block only runs when
+the run's environment, from --env or else a parameter named env, is one of
+those listed; otherwise it is skipped:
+
+  ` + "```bash {destructive confirm=\"<namespace>\" env=prod,staging}" + `
+  kubectl delete namespace <namespace>
+  ` + "```" + `
+
+Without an environment, env blocks are skipped. When the section declares an
+env parameter with allowed values, --env must be one of those or of the
+environments in env=, so that a typo cannot skip steps unnoticed. Confirm
+phrases cannot use secret parameters, which would be shown masked.
+
+--yes does not cover these confirmations, so guarded blocks still ask, and
+without a terminal pm run refuses to start. Add --force to run them without
+asking as well.
+
+Parameters declared in the section's frontmatter are passed to the blocks as
+environment variables, $NAME and ${NAME} as in pm open, and <name> stands
+for ${NAME}: the shell expands it like any variable, so quote it where a
+value may contain spaces, and it is not expanded inside single quotes. Blocks
+are shown with <name> replaced by the value, secret ones masked. Values come
+from --set name=value, defaults and prompts, and missing ones are reported
+before any block is shown.
+
+Each run is recorded in .pm/.sessions/ (see 'pm sessions'): the section,
+parameters with secrets redacted, and every step's command, timing and exit
+code. Add --log-output to record the end of each step's output as well; blocks
+then write to a pipe rather than the terminal, so leave it off for blocks
+that need one or that leave processes running in the background.
+
+Use --yes to run every block without asking, e.g. from a script, and
+--dry-run to print the blocks as they would run, with <name> replaced and
+secrets masked, without running or recording anything.`,
+	Args:              checkArgs(cobra.MaximumNArgs(1)),
+	ValidArgsFunction: completeSection,
+	RunE:              runRun,
+}
+
+func init() {
+	runCmd.Flags().BoolVarP(&runYesFlag, "yes", "y", false, "run every step without asking")
+	addSetFlag(runCmd, &runSetFlags)
+	runCmd.Flags().BoolVar(&runNoLogFlag, "no-log", false, "do not record the run in .pm/.sessions/")
+	runCmd.Flags().BoolVar(&runLogOutput, "log-output", false, "also record each step's output in the session log")
+	runCmd.Flags().BoolVar(&runDryRunFlag, "dry-run", false, "print the steps with parameters substituted instead of running them")
+	runCmd.Flags().StringVar(&runEnvFlag, "env", "", "environment the run targets, for steps annotated with env= (default: the env parameter)")
+	runCmd.Flags().BoolVar(&runForceFlag, "force", false, "run destructive and confirm steps without asking again")
+	rootCmd.AddCommand(runCmd)
+}
+
+// Answers to the per-step prompt, in the order of stepChoices.
+const (
+	stepRun = iota
+	stepSkip
+	stepEdit
+	stepAbort
+)
+
+var stepChoices = []cli.Choice{
+	{Key: "y", Label: "run"},
+	{Key: "s", Label: "skip"},
+	{Key: "e", Label: "edit"},
+	{Key: "a", Label: "abort"},
+}
+
+func runRun(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	args, slug := splitHeading(args)
+	given, err := parseSets(cmd, runSetFlags)
+	if err != nil {
+		return err
+	}
+	interactive := isInteractive()
+	if !runYesFlag && !runDryRunFlag && !interactive {
+		return &usageError{fmt.Errorf("refusing to run steps without confirmation; pass --yes (see '%s --help')", cmd.CommandPath())}
+	}
+
+	layers, err := projectLayers()
+	if err != nil {
+		return err
+	}
+	entry, ok, err := resolveSection(cmd, layers, args)
+	if !ok {
+		return err
+	}
+	s, err := loadSection(entry)
+	if err != nil {
+		return err
+	}
+	if slug != "" {
+		h, body, err := manual.ExtractHeading(s.Body, slug)
+		if err != nil {
+			return fmt.Errorf("%s/%s: %w", s.Group, s.Name, err)
+		}
+		s.Body, slug = body, h.Slug
+	}
+
+	steps, err := shellSteps(s)
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	params := manual.UsedParams(stepsText(steps, runEnvFlag == ""), s.Params)
+	values, err := resolveParams(cmd, scanner, s, params, given, !runYesFlag && interactive)
+	if err != nil {
+		return err
+	}
+	masked := maskSecrets(params, values)
+	env := runEnvFlag
+	if env == "" {
+		env = values["env"]
+	} else if err := checkEnv(cmd, s, steps, env); err != nil {
+		return err
+	}
+	if err := checkGuards(cmd, steps, interactive); err != nil {
+		return err
+	}
+
+	opts := cli.MarkdownOptions{Width: terminalWidth(w), Color: colorEnabled(w)}
+	show := func(n int, step manual.CodeBlock) {
+		step.Code = manual.SubstituteNames(step.Code, params, masked)
+		cli.PrintStep(w, n, len(steps), step, opts)
+	}
+	if runDryRunFlag {
+		dryRun(w, steps, env, show)
+		return nil
+	}
+
+	var rec *sessionRecorder
+	if !runNoLogFlag {
+		rec = startSession(cmd, layers[0], fs.SessionEvent{
+			Section: entry.Group + "/" + entry.Name,
+			Heading: slug,
+			Layer:   entry.Layer.Name,
+			Params:  masked,
+		}, secretValues(params, values))
+	}
+	status := fs.StatusFailed
+	defer func() { rec.finish(status) }()
+
+	dir := runDir(entry)
+	ran, skipped := 0, 0
+	for i, step := range steps {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		show(i+1, step)
+
+		event := fs.SessionEvent{Step: i + 1, Total: len(steps), Headings: step.Path, Action: fs.ActionRun}
+		if envs := step.Envs(); envs != nil && !slices.Contains(envs, env) {
+			fmt.Fprintf(w, "Skipped: only runs in env %s (this run: %s).\n", strings.Join(envs, " or "), envName(env))
+			skipped++
+			event.Action = fs.ActionSkip
+			event.Command = manual.SubstituteNames(step.Code, params, masked)
+			rec.step(event, nil)
+			continue
+		}
+		if !runYesFlag {
+			original := step.Code
+			choice, err := promptStep(cmd, scanner, &step, func() { show(i+1, step) })
+			if errors.Is(err, io.EOF) {
+				choice, err = stepAbort, nil
+			}
+			if err != nil {
+				return err
+			}
+			event.Edited = step.Code != original
+			switch choice {
+			case stepSkip:
+				skipped++
+				event.Action = fs.ActionSkip
+				event.Command = manual.SubstituteNames(step.Code, params, masked)
+				rec.step(event, nil)
+				continue
+			case stepAbort:
+				fmt.Fprintf(w, "Aborted after %d of %d step(s).\n", i, len(steps))
+				status = fs.StatusAborted
+				return nil
+			}
+		}
+		if step.Guarded() && !runForceFlag {
+			phrase := manual.Substitute(step.Attrs["confirm"], params, masked)
+			ok, err := confirmStep(cmd, scanner, step, phrase)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Fprintf(w, "Aborted after %d of %d step(s).\n", i, len(steps))
+				status = fs.StatusAborted
+				return nil
+			}
+		}
+
+		event.Command = manual.SubstituteNames(step.Code, params, masked)
+		event.Time = time.Now()
+		step.Code = manual.SubstituteNames(step.Code, params, paramRefs(params))
+		var capture io.Writer
+		if runLogOutput {
+			capture = rec.capture()
+		}
+		err := runStep(cmd, step, dir, paramEnv(params, values), capture)
+		var exit *exec.ExitError
+		if err != nil && !errors.As(err, &exit) {
+			return err
+		}
+		rec.step(event, exit)
+		ran++
+		if exit != nil {
+			if !step.Has("allow-fail") {
+				return fmt.Errorf("step %d/%d failed: %w", i+1, len(steps), exit)
+			}
+			fmt.Fprintf(w, "Step %d failed: %v (allowed to fail)\n", i+1, exit)
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Ran %d of %d step(s)", ran, len(steps))
+	if skipped > 0 {
+		fmt.Fprintf(w, ", skipped %d", skipped)
+	}
+	fmt.Fprintln(w, ".")
+	status = fs.StatusCompleted
+	return nil
+}
+
+// stepsText returns the text of the steps in which parameters may appear: their
+// code and confirmation phrases, plus the env parameter when withEnv is set and
+// a step is restricted to an environment.
+func stepsText(steps []manual.CodeBlock, withEnv bool) string {
+	var text strings.Builder
+	for _, step := range steps {
+		text.WriteString(step.Code)
+		text.WriteString(step.Attrs["confirm"])
+		if withEnv && step.Envs() != nil {
+			text.WriteString("<env>")
+		}
+	}
+	return text.String()
+}
+
+// checkEnv returns an error if env, given with --env, is none of the
+// environments the section declares: the allowed values of its env
+// parameter, plus those steps are restricted to. A section without such a
+// parameter accepts any env, skipping the steps restricted to others.
+func checkEnv(cmd *cobra.Command, s manual.Section, steps []manual.CodeBlock, env string) error {
+	i := slices.IndexFunc(s.Params, func(p manual.Param) bool { return p.Name == "env" })
+	if i < 0 || len(s.Params[i].Values) == 0 {
+		return nil
+	}
+	known := slices.Clone(s.Params[i].Values)
+	for _, step := range steps {
+		known = append(known, step.Envs()...)
+	}
+	if slices.Contains(known, env) {
+		return nil
+	}
+	slices.Sort(known)
+	return &usageError{fmt.Errorf("unknown env %q (known: %s) (see '%s --help')", env, strings.Join(slices.Compact(known), ", "), cmd.CommandPath())}
+}
+
+// checkGuards returns an error, before any step runs, if a step needs a
+// confirmation that cannot be given. A dry run needs no confirmations.
+func checkGuards(cmd *cobra.Command, steps []manual.CodeBlock, interactive bool) error {
+	for i, step := range steps {
+		if step.Guarded() && !runForceFlag && !runDryRunFlag && !interactive {
+			return &usageError{fmt.Errorf("step %d/%d needs confirmation at a terminal; pass --force to run it without asking (see '%s --help')", i+1, len(steps), cmd.CommandPath())}
+		}
+	}
+	return nil
+}
+
+// envName describes the run's environment in messages about skipped steps.
+func envName(env string) string {
+	if env == "" {
+		return "none given"
+	}
+	return env
+}
+
+// confirmStep asks for the extra confirmation of a guarded step: typing its
+// confirmation phrase, or a yes that defaults to no for a destructive one.
+func confirmStep(cmd *cobra.Command, scanner *bufio.Scanner, step manual.CodeBlock, phrase string) (bool, error) {
+	w := cmd.OutOrStdout()
+	if !step.Has("confirm") {
+		return cli.ConfirmYesNo(scanner, w, "This step is destructive. Run it?", false)
+	}
+
+	typed, err := cli.PromptValue(scanner, w, fmt.Sprintf("Type %q to confirm", phrase))
+	if errors.Is(err, io.EOF) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if typed != strings.TrimSpace(phrase) {
+		fmt.Fprintln(w, "Confirmation did not match.")
+		return false, nil
+	}
+	return true, nil
+}
+
+// dryRun prints each step as it would run and whether it would be skipped.
+func dryRun(w io.Writer, steps []manual.CodeBlock, env string, show func(int, manual.CodeBlock)) {
+	skipped := 0
+	for i, step := range steps {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		show(i+1, step)
+		if envs := step.Envs(); envs != nil && !slices.Contains(envs, env) {
+			fmt.Fprintf(w, "Would skip: only runs in env %s (this run: %s).\n", strings.Join(envs, " or "), envName(env))
+			skipped++
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Dry run: %d of %d step(s) would run", len(steps)-skipped, len(steps))
+	if skipped > 0 {
+		fmt.Fprintf(w, ", %d skipped", skipped)
+	}
+	fmt.Fprintln(w, "; nothing was run.")
+}
+
+// shellSteps returns the section's shell code blocks, rejecting unknown
+// annotations and confirmation phrases that cannot be typed before anything
+// runs.
+func shellSteps(s manual.Section) ([]manual.CodeBlock, error) {
+	blocks, err := manual.ParseCodeBlocks(s.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s/%s: %w", s.Group, s.Name, err)
+	}
+	var steps []manual.CodeBlock
+	for _, b := range blocks {
+		if !b.IsShell() {
+			continue
+		}
+		if err := b.CheckAnnotations(); err != nil {
+			return nil, fmt.Errorf("%s/%s: %w", s.Group, s.Name, err)
+		}
+		for _, p := range manual.UsedParams(b.Attrs["confirm"], s.Params) {
+			if p.Secret {
+				return nil, fmt.Errorf("%s/%s: line %d: confirm phrase uses secret parameter %s, which would be shown masked", s.Group, s.Name, b.Line, p.Name)
+			}
+		}
+		steps = append(steps, b)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("%s/%s has no shell code blocks to run", s.Group, s.Name)
+	}
+	return steps, nil
+}
+
+// promptStep asks what to do with a step until it is run, skipped or aborted.
+// Editing replaces step's code for this run and shows the step again.
+func promptStep(cmd *cobra.Command, scanner *bufio.Scanner, step *manual.CodeBlock, show func()) (int, error) {
+	w := cmd.OutOrStdout()
+	for {
+		choice, err := cli.Choose(scanner, w, "Run this step?", stepChoices, stepRun)
+		if err != nil || choice != stepEdit {
+			return choice, err
+		}
+		if step.Code, err = editStep(cmd, step.Code); err != nil {
+			return 0, err
+		}
+		fmt.Fprintln(w)
+		show()
+	}
+}
+
+// editStep opens code in $EDITOR in a temporary file and returns the result.
+func editStep(cmd *cobra.Command, code string) (string, error) {
+	f, err := os.CreateTemp("", "pm-step-*.sh")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(code); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	if err := openInEditor(cmd, f.Name()); err != nil {
+		return "", err
+	}
+	edited, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(edited), nil
+}
+
+// stepWaitDelay is how long runStep keeps copying output after a block
+// exits, for background processes it started.
+const stepWaitDelay = time.Second
+
+// runStep runs a block in a subshell with the terminal's stdin, streaming its
+// output. A block that fails returns an *exec.ExitError. While it runs, Ctrl-C
+// goes to the block rather than stopping pm, so it is reported like any other
+// failure. env is added to pm's environment. If capture is not nil, output
+// is copied to it as well; then output of background processes the block
+// leaves running is only copied until stepWaitDelay after it exits.
+func runStep(cmd *cobra.Command, step manual.CodeBlock, dir string, env []string, capture io.Writer) error {
+	c := exec.Command(stepShell(step.Lang), "-e", "-c", step.Code)
+	c.Dir = dir
+	c.Env = append(os.Environ(), env...)
+	c.Stdin = os.Stdin
+	c.Stdout = cmd.OutOrStdout()
+	c.Stderr = cmd.ErrOrStderr()
+	if capture != nil {
+		c.Stdout = io.MultiWriter(c.Stdout, capture)
+		c.Stderr = io.MultiWriter(c.Stderr, capture)
+		c.WaitDelay = stepWaitDelay
+	}
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	err := c.Run()
+	if errors.Is(err, exec.ErrWaitDelay) {
+		return nil
+	}
+	return err
+}
+
+// stepShell returns the shell for a block's language: bash or zsh when the
+// block asks for it and it is installed, otherwise sh.
+func stepShell(lang string) string {
+	if lang == "bash" || lang == "zsh" {
+		if _, err := exec.LookPath(lang); err == nil {
+			return lang
+		}
+	}
+	return "sh"
+}
+
+// runDir returns the directory steps run in: the project directory holding the
+// section's .pm/, or the working directory for the global manual.
+func runDir(e fs.Entry) string {
+	if e.Layer.Global {
+		wd, _ := os.Getwd()
+		return wd
+	}
+	return e.Layer.Root
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hojooneum/pm/internal/manual"
+)
+
+func TestCheckEnv(t *testing.T) {
+	s, err := manual.ParseSection("deploy", "core", "---\nparams:\n  - name: env\n    values: [dev, staging, prod]\n---\n"+
+		"```bash {env=prod}\nmake release\n```\n\n```bash {env=prod,qa}\nmake smoke\n```\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps, err := shellSteps(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, env := range []string{"prod", "qa", "dev"} {
+		if err := checkEnv(runCmd, s, steps, env); err != nil {
+			t.Errorf("%s: %v", env, err)
+		}
+	}
+	err = checkEnv(runCmd, s, steps, "prd")
+	if _, ok := err.(*usageError); !ok || !strings.Contains(err.Error(), "known: dev, prod, qa, staging") {
+		t.Errorf("expected a usage error listing the known envs, got %v", err)
+	}
+
+	// Without an env parameter listing values, any env goes: steps restricted
+	// to others are skipped.
+	s.Params = nil
+	for _, env := range []string{"staging", "prd"} {
+		if err := checkEnv(runCmd, s, steps, env); err != nil {
+			t.Errorf("%s without an env parameter: %v", env, err)
+		}
+	}
+}
+
+func TestShellSteps_SecretConfirm(t *testing.T) {
+	s, err := manual.ParseSection("rotate", "core", "---\nparams:\n  - name: token\n    secret: true\n  - name: host\n---\n"+
+		"```bash {confirm=\"rotate <host>\"}\nrotate\n```\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := shellSteps(s); err != nil {
+		t.Errorf("expected a public parameter to be allowed, got %v", err)
+	}
+
+	s.Body = "```bash {confirm=\"rotate $TOKEN\"}\nrotate\n```\n"
+	if _, err := shellSteps(s); err == nil || !strings.Contains(err.Error(), "secret parameter token") {
+		t.Errorf("expected an error for a secret parameter, got %v", err)
+	}
+}
@@ -1,38 +1,223 @@
 package cmd
 
 import (
-	"os"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/hojooneum/pm/internal/cli"
 	"github.com/hojooneum/pm/internal/fs"
 	"github.com/spf13/cobra"
 )
 
+var (
+	searchTagFlags    []string
+	searchAnyTagsFlag bool
+	searchOpts        fs.SearchOptions
+	searchContextFlag int
+	searchRankFlag    bool
+)
+
 var searchCmd = &cobra.Command{
-	Use:   "search <keyword>",
-	Short: "Search for a keyword across all sections",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runSearch,
+	Use:   "search <term>...",
+	Short: "Search for terms across all sections",
+	Long: `Search for terms across all sections.
+
+Terms are combined with AND. Use OR between terms for alternatives, and
+prefix a term with "-" (or precede it with NOT) to exclude it:
+
+  pm search postgres -replica
+  pm search timeout OR deadline
+  pm search --section rollback kubectl
+  pm search --rank postgres failover
+
+Put terms that look like flags after "--".`,
+	Args:              cobra.ArbitraryArgs,
+	ValidArgsFunction: completeSearch,
+	RunE:              runSearch,
+
+	// Flags are parsed by runSearch so that "-term" exclusions are not
+	// mistaken for unknown shorthand flags.
+	DisableFlagParsing: true,
 }
 
 func init() {
+	searchCmd.Flags().BoolVar(&searchOpts.Regex, "regex", false, "treat terms as regular expressions")
+	searchCmd.Flags().BoolVar(&searchOpts.Word, "word", false, "match whole words only")
+	searchCmd.Flags().BoolVar(&searchOpts.CaseSensitive, "case-sensitive", false, "match case-sensitively")
+	searchCmd.Flags().BoolVar(&searchOpts.Section, "section", false, "match terms anywhere in a section instead of on a single line")
+	searchCmd.Flags().BoolVar(&searchRankFlag, "rank", false, "rank sections by relevance (BM25), best first, using the index in .pm/.cache")
+	searchCmd.Flags().IntVarP(&searchOpts.After, "after-context", "A", 0, "print `N` lines of context after each match")
+	searchCmd.Flags().IntVarP(&searchOpts.Before, "before-context", "B", 0, "print `N` lines of context before each match")
+	searchCmd.Flags().IntVarP(&searchContextFlag, "context", "C", 0, "print `N` lines of context around each match")
+	addTagFlags(searchCmd, &searchTagFlags, &searchAnyTagsFlag)
 	rootCmd.AddCommand(searchCmd)
 }
 
 func runSearch(cmd *cobra.Command, args []string) error {
-	root, _ := os.Getwd()
 	w := cmd.OutOrStdout()
 
-	if !fs.DetectPMDir(root) {
-		cli.PrintNoPMDir(w)
-		return nil
+	words, err := parseSearchArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+	if help, _ := cmd.Flags().GetBool("help"); help {
+		return cmd.Help()
+	}
+	if err := applyOutputFlag(); err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		return &usageError{fmt.Errorf("requires at least one search term (see '%s --help')", cmd.CommandPath())}
+	}
+
+	if searchContextFlag > 0 {
+		searchOpts.Before = max(searchOpts.Before, searchContextFlag)
+		searchOpts.After = max(searchOpts.After, searchContextFlag)
+	}
+
+	query, err := fs.ParseQuery(words)
+	if err != nil {
+		return &usageError{err}
+	}
+	if searchRankFlag && (searchOpts.Regex || searchOpts.Word || searchOpts.CaseSensitive || searchOpts.Section) {
+		return &usageError{fmt.Errorf("--rank matches whole words per section and cannot be combined with --regex, --word, --case-sensitive or --section")}
+	}
+	matcher, err := fs.NewMatcher(query, searchOpts)
+	if err != nil {
+		return &usageError{err}
+	}
+
+	layers, err := projectLayers()
+	if err != nil {
+		return err
 	}
 
-	results, err := fs.Search(root, args[0])
+	var results []fs.SearchResult
+	if searchRankFlag {
+		results, err = fs.RankedSearch(layers, query, searchOpts)
+	} else {
+		results, err = fs.SearchLayers(layers, matcher)
+	}
 	if err != nil {
 		return err
 	}
 
+	if len(searchTagFlags) > 0 {
+		results, err = filterResultsByTags(cmd.ErrOrStderr(), layers, results, searchTagFlags, searchAnyTagsFlag)
+		if err != nil {
+			return err
+		}
+	}
+
+	if structuredOutput() {
+		return cli.Render(w, outputFormat, cli.NewSearchOutput(results))
+	}
+
 	cli.PrintSearchResults(w, results)
 	return nil
 }
+
+// filterResultsByTags keeps the results whose section carries the requested
+// tags. Sections whose frontmatter cannot be read are dropped with a warning on w.
+func filterResultsByTags(w io.Writer, layers []fs.Layer, results []fs.SearchResult, tags []string, matchAny bool) ([]fs.SearchResult, error) {
+	keep := make(map[string]bool)
+	var out []fs.SearchResult
+	for _, r := range results {
+		key := r.Layer + ":" + r.File
+		ok, checked := keep[key]
+		if !checked {
+			e, found := resultEntry(layers, r)
+			if found {
+				_, loaded, err := loadEntries(w, []fs.Entry{e})
+				if err != nil {
+					return nil, err
+				}
+				ok = len(loaded) == 1 && loaded[0].HasTags(tags, matchAny)
+			}
+			keep[key] = ok
+		}
+		if ok {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// resultEntry maps a search result back to the section entry it came from.
+func resultEntry(layers []fs.Layer, r fs.SearchResult) (fs.Entry, bool) {
+	group, file := filepath.Split(r.File)
+	group = filepath.Clean(group)
+	for _, l := range layers {
+		if r.Layer == "" || l.Name == r.Layer {
+			return fs.Entry{Layer: l, Group: group, Name: strings.TrimSuffix(file, ".md")}, true
+		}
+	}
+	return fs.Entry{}, false
+}
+
+// parseSearchArgs separates flags from search terms and parses the flags.
+// An argument starting with a single "-" is a flag only if it is a registered
+// shorthand such as "-o" or "-A", with its value in the next argument or
+// attached to it as in "-C2" (see validShorthandValue); otherwise it is an
+// excluded term such as "-replica" or "-old". Everything after "--" is a term.
+func parseSearchArgs(cmd *cobra.Command, args []string) (terms []string, err error) {
+	// With DisableFlagParsing cobra does not merge the root's persistent
+	// flags into the command's, so add them before looking anything up.
+	flags := cmd.Flags()
+	flags.AddFlagSet(cmd.InheritedFlags())
+
+	var flagArgs []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			terms = append(terms, args[i+1:]...)
+			i = len(args)
+		case strings.HasPrefix(arg, "--"):
+			flagArgs = append(flagArgs, arg)
+			name, _, hasValue := strings.Cut(arg[2:], "=")
+			if f := flags.Lookup(name); f != nil && !hasValue && f.NoOptDefVal == "" && i+1 < len(args) {
+				i++
+				flagArgs = append(flagArgs, args[i])
+			}
+		case len(arg) > 1 && arg[0] == '-':
+			f := flags.ShorthandLookup(arg[1:2])
+			switch {
+			case f == nil:
+				terms = append(terms, arg)
+			case len(arg) == 2:
+				flagArgs = append(flagArgs, arg)
+				if f.NoOptDefVal == "" && i+1 < len(args) {
+					i++
+					flagArgs = append(flagArgs, args[i])
+				}
+			case f.NoOptDefVal == "" && validShorthandValue(f.Name, arg[2:]):
+				flagArgs = append(flagArgs, arg)
+			default:
+				terms = append(terms, arg)
+			}
+		default:
+			terms = append(terms, arg)
+		}
+	}
+
+	if err := flags.Parse(flagArgs); err != nil {
+		return nil, flagError(cmd, err)
+	}
+	return terms, nil
+}
+
+// validShorthandValue reports whether value, attached to the shorthand of the
+// named flag as in -C2, is one the flag accepts: an output format for
+// --output, and a number for the context flags.
+func validShorthandValue(name, value string) bool {
+	if name == "output" {
+		_, err := cli.ParseFormat(value)
+		return err == nil
+	}
+	_, err := strconv.Atoi(value)
+	return err == nil
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseSearchArgs(t *testing.T) {
+	searchCmd.InitDefaultHelpFlag()
+
+	tests := []struct {
+		args   []string
+		terms  []string
+		output string
+		after  int
+	}{
+		{[]string{"postgres", "-hostname"}, []string{"postgres", "-hostname"}, "text", 0},
+		{[]string{"deploy", "-old"}, []string{"deploy", "-old"}, "text", 0},
+		{[]string{"deploy", "-Abc"}, []string{"deploy", "-Abc"}, "text", 0},
+		{[]string{"-C2", "deploy"}, []string{"deploy"}, "text", 0},
+		{[]string{"deploy", "-ojson"}, []string{"deploy"}, "json", 0},
+		{[]string{"deploy", "-A3"}, []string{"deploy"}, "text", 3},
+		{[]string{"deploy", "-x"}, []string{"deploy", "-x"}, "text", 0},
+		{[]string{"-o", "json", "deploy", "-replica"}, []string{"deploy", "-replica"}, "json", 0},
+		{[]string{"deploy", "-A", "3"}, []string{"deploy"}, "text", 3},
+		{[]string{"--after-context=2", "--output", "yaml", "deploy"}, []string{"deploy"}, "yaml", 2},
+		{[]string{"deploy", "--", "-o", "--rank"}, []string{"deploy", "-o", "--rank"}, "text", 0},
+	}
+	for _, tt := range tests {
+		outputFlag, searchOpts.After, searchContextFlag = "text", 0, 0
+		terms, err := parseSearchArgs(searchCmd, tt.args)
+		if err != nil {
+			t.Errorf("%q: %v", tt.args, err)
+			continue
+		}
+		if !slices.Equal(terms, tt.terms) || outputFlag != tt.output || searchOpts.After != tt.after {
+			t.Errorf("%q: got terms %q, output %s, after %d; want %q, %s, %d",
+				tt.args, terms, outputFlag, searchOpts.After, tt.terms, tt.output, tt.after)
+		}
+	}
+
+	searchContextFlag = 0
+	if _, err := parseSearchArgs(searchCmd, []string{"-C2", "deploy"}); err != nil || searchContextFlag != 2 {
+		t.Errorf("-C2: got context %d, %v; want 2", searchContextFlag, err)
+	}
+
+	if help, _ := searchCmd.Flags().GetBool("help"); help {
+		t.Error("expected -hostname not to set --help")
+	}
+	if _, err := parseSearchArgs(searchCmd, []string{"deploy", "--no-such-flag"}); err == nil {
+		t.Error("expected an error for an unknown long flag")
+	}
+}
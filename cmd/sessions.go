@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/hojooneum/pm/internal/cli"
+	"github.com/hojooneum/pm/internal/fs"
+	"github.com/spf13/cobra"
+)
+
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "List, show and export recorded pm run sessions",
+	Long: `List, show and export the sessions recorded by pm run.
+
+Each run of pm run writes a log to .pm/.sessions/ in the nearest manual: who
+ran which section with which parameters, and every step's command, timing,
+exit code and, with --log-output, the end of its output. Secret parameters
+are redacted. Logs are JSON Lines files, one event per line, so they can also
+be read with other tools. The directory ignores itself in git, so logs are
+never committed with the manual.
+
+Sessions are named by their UTC start time and section, e.g.
+20261018T142501Z-deploy. Commands taking an ID accept any unique prefix of
+one, and default to the most recent session.`,
+}
+
+var sessionsListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List recorded sessions",
+	Args:    checkArgs(cobra.NoArgs),
+	RunE:    runSessionsList,
+}
+
+var sessionsShowCmd = &cobra.Command{
+	Use:               "show [id]",
+	Short:             "Show a session's steps and output",
+	Args:              checkArgs(cobra.MaximumNArgs(1)),
+	ValidArgsFunction: completeSession,
+	RunE:              runSessionsShow,
+}
+
+var sessionsExportCmd = &cobra.Command{
+	Use:   "export [id]",
+	Short: "Export a session as a markdown timeline",
+	Long: `Write a session to stdout as markdown: a summary, a timeline table and
+each step's command and output, ready to paste into a postmortem.
+
+  pm sessions export > postmortem-timeline.md`,
+	Args:              checkArgs(cobra.MaximumNArgs(1)),
+	ValidArgsFunction: completeSession,
+	RunE:              runSessionsExport,
+}
+
+func init() {
+	sessionsCmd.AddCommand(sessionsListCmd, sessionsShowCmd, sessionsExportCmd)
+	rootCmd.AddCommand(sessionsCmd)
+}
+
+func runSessionsList(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	layers, err := projectLayers()
+	if err != nil {
+		return err
+	}
+	sessions, err := fs.ListSessions(layers[0])
+	if err != nil {
+		return err
+	}
+
+	if structuredOutput() {
+		out := make([]cli.SessionOutput, len(sessions))
+		for i, s := range sessions {
+			out[i] = cli.NewSessionOutput(s, nil)
+		}
+		return cli.Render(w, outputFormat, out)
+	}
+	cli.PrintSessionList(w, sessions)
+	return nil
+}
+
+func runSessionsShow(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	s, events, err := readSession(args)
+	if err != nil {
+		return err
+	}
+
+	if structuredOutput() {
+		return cli.Render(w, outputFormat, cli.NewSessionOutput(s, events))
+	}
+	cli.PrintSession(w, s, events)
+	return nil
+}
+
+func runSessionsExport(cmd *cobra.Command, args []string) error {
+	s, events, err := readSession(args)
+	if err != nil {
+		return err
+	}
+	cli.ExportSessionMarkdown(cmd.OutOrStdout(), s, events)
+	return nil
+}
+
+// readSession reads the session named by the optional ID argument from the
+// nearest manual.
+func readSession(args []string) (fs.Session, []fs.SessionEvent, error) {
+	layers, err := projectLayers()
+	if err != nil {
+		return fs.Session{}, nil, err
+	}
+	var id string
+	if len(args) == 1 {
+		id = args[0]
+	}
+	id, events, err := fs.ReadSession(layers[0], id)
+	if err != nil {
+		return fs.Session{}, nil, err
+	}
+	return fs.SummarizeSession(id, events), events, nil
+}
+
+// completeSession completes session IDs, most recent first.
+func completeSession(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	layers, err := projectLayers()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	sessions, err := fs.ListSessions(layers[0])
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var ids []string
+	for i := len(sessions) - 1; i >= 0; i-- {
+		if s := sessions[i]; strings.HasPrefix(s.ID, toComplete) {
+			ids = append(ids, s.ID+"\t"+s.Section)
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
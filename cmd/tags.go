@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"github.com/hojooneum/pm/internal/cli"
+	"github.com/hojooneum/pm/internal/manual"
+	"github.com/spf13/cobra"
+)
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "List all tags with the number of sections using each",
+	Args:  checkArgs(cobra.NoArgs),
+	RunE:  runTags,
+}
+
+func init() {
+	rootCmd.AddCommand(tagsCmd)
+}
+
+func runTags(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	layers, err := projectLayers()
+	if err != nil {
+		return err
+	}
+
+	sections, err := loadAllSections(cmd.ErrOrStderr(), layers)
+	if err != nil {
+		return err
+	}
+
+	tags := manual.CountTags(sections)
+	if structuredOutput() {
+		return cli.Render(w, outputFormat, cli.NewTagOutput(tags))
+	}
+
+	cli.PrintTagList(w, tags)
+	return nil
+}
+
+// addTagFlags registers the --tag and --any-tag filter flags on c.
+func addTagFlags(c *cobra.Command, tags *[]string, matchAny *bool) {
+	c.Flags().StringSliceVar(tags, "tag", nil, "only include sections with this tag (repeatable; all must match)")
+	c.Flags().BoolVar(matchAny, "any-tag", false, "match sections with any of the --tag values instead of all")
+	_ = c.RegisterFlagCompletionFunc("tag", completeTag)
+}
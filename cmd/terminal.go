@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"strconv"
+
+	"golang.org/x/term"
+)
+
+// terminalFile returns w as an *os.File if it is a terminal.
+func terminalFile(w io.Writer) (*os.File, bool) {
+	f, ok := w.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return nil, false
+	}
+	return f, true
+}
+
+// colorEnabled reports whether ANSI styles should be written to w: it must be
+// a terminal, NO_COLOR must be unset or empty (see no-color.org), and TERM must
+// not be "dumb".
+func colorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	_, ok := terminalFile(w)
+	return ok
+}
+
+// terminalWidth returns the width of the terminal w writes to, or $COLUMNS
+// when w is not a terminal. It returns 0 when neither is known.
+func terminalWidth(w io.Writer) int {
+	if f, ok := terminalFile(w); ok {
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil && width > 0 {
+			return width
+		}
+	}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return 0
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"github.com/hojooneum/pm/internal/cli"
+	"github.com/hojooneum/pm/internal/manual"
+	"github.com/spf13/cobra"
+)
+
+var tocCmd = &cobra.Command{
+	Use:               "toc [section]",
+	Short:             "Show a section's heading outline with slugs",
+	Long:              "Show a section's heading outline. Each heading is followed by the slug\nto open it on its own with 'pm open <section>#<slug>'.",
+	Args:              checkArgs(cobra.MaximumNArgs(1)),
+	ValidArgsFunction: completeSection,
+	RunE:              runTOC,
+}
+
+func init() {
+	rootCmd.AddCommand(tocCmd)
+}
+
+func runTOC(cmd *cobra.Command, args []string) error {
+	w := cmd.OutOrStdout()
+
+	layers, err := projectLayers()
+	if err != nil {
+		return err
+	}
+
+	entry, ok, err := resolveSection(cmd, layers, args)
+	if !ok {
+		return err
+	}
+
+	s, err := loadSection(entry)
+	if err != nil {
+		return err
+	}
+
+	headings := manual.ParseHeadings(s.Body)
+	if structuredOutput() {
+		return cli.Render(w, outputFormat, cli.NewHeadingOutput(headings))
+	}
+
+	cli.PrintTOC(w, s, headings)
+	return nil
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// PrintChecklist writes progress through a section's checklist to w: each
+// item under its headings, ticked or not, with who ticked it and when.
+func PrintChecklist(w io.Writer, c ChecklistOutput) {
+	fmt.Fprintf(w, "%s: %s", ChecklistName(c), ChecklistProgress(c))
+	if c.Started != nil {
+		fmt.Fprintf(w, ", started %s", c.Started.UTC().Format("2006-01-02 15:04 MST"))
+	}
+	fmt.Fprintln(w)
+
+	var path []string
+	for i, item := range c.Items {
+		if i == 0 || !slices.Equal(item.Headings, path) {
+			path = item.Headings
+			fmt.Fprintln(w)
+			if len(path) > 0 {
+				fmt.Fprintln(w, strings.Join(path, " > "))
+			}
+		}
+		fmt.Fprintln(w, "  "+ChecklistItem(item))
+	}
+}
+
+// ChecklistName names a checklist and its run, if any, for headers.
+func ChecklistName(c ChecklistOutput) string {
+	if c.Run == "" {
+		return "Checklist for " + c.Section
+	}
+	return fmt.Sprintf("Checklist for %s (run %s)", c.Section, c.Run)
+}
+
+// ChecklistProgress summarizes how many items are done.
+func ChecklistProgress(c ChecklistOutput) string {
+	if c.Done == c.Total {
+		return fmt.Sprintf("all %d item(s) done", c.Total)
+	}
+	return fmt.Sprintf("%d of %d item(s) done", c.Done, c.Total)
+}
+
+// ChecklistItem formats an item as a checkbox and its text, followed for a
+// done item by who ticked it and when.
+func ChecklistItem(item ChecklistItemOutput) string {
+	if !item.Done {
+		return "☐ " + item.Text
+	}
+	switch {
+	case item.Time == nil:
+		return "☑ " + item.Text + "  (ticked in the section)"
+	case item.User != "":
+		return fmt.Sprintf("☑ %s  (%s, %s)", item.Text, item.User, item.Time.UTC().Format("2006-01-02 15:04 MST"))
+	default:
+		return fmt.Sprintf("☑ %s  (%s)", item.Text, item.Time.UTC().Format("2006-01-02 15:04 MST"))
+	}
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/hojooneum/pm/internal/fs"
+	"github.com/hojooneum/pm/internal/manual"
+)
+
+func TestPrintChecklist(t *testing.T) {
+	tasks := manual.ParseTasks("# Deploy\n\n## Before\n\n- [ ] Tests pass\n- [x] Plan written\n\n## After\n\n- [ ] Dashboards checked\n")
+	ticked := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	c := fs.Checklist{
+		Section: "core/deploy",
+		Run:     "v1.42",
+		Started: ticked,
+		Done: map[string]fs.ChecklistMark{
+			"Deploy > Before > Tests pass":    {Time: ticked, User: "ana"},
+			"Deploy > Before > Removed since": {Time: ticked},
+		},
+	}
+
+	out := NewChecklistOutput(tasks, c)
+	if out.Done != 2 || out.Total != 3 {
+		t.Errorf("got %d of %d done, want 2 of 3", out.Done, out.Total)
+	}
+
+	var buf bytes.Buffer
+	PrintChecklist(&buf, out)
+	want := "Checklist for core/deploy (run v1.42): 2 of 3 item(s) done, started 2026-10-18 09:30 UTC\n" +
+		"\n" +
+		"Deploy > Before\n" +
+		"  ☑ Tests pass  (ana, 2026-10-18 09:30 UTC)\n" +
+		"  ☑ Plan written  (ticked in the section)\n" +
+		"\n" +
+		"Deploy > After\n" +
+		"  ☐ Dashboards checked\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
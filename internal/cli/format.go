@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -11,16 +12,23 @@ import (
 
 // PrintSectionList writes a grouped list of sections to w.
 // Groups are printed in the order they first appear in the input.
-func PrintSectionList(w io.Writer, sections []manual.Section) {
+// When sections come from more than one .pm/ layer, each line notes its layer.
+// groups supplies titles, icons and descriptions from _group.yaml files; groups
+// without one are headed "<Group> sections".
+// fields names extra frontmatter keys (e.g. "owner") to print as key=value pairs.
+func PrintSectionList(w io.Writer, sections []manual.Section, groups map[string]fs.GroupMeta, fields ...string) {
 	// Collect groups in first-appearance order
 	var groupOrder []string
 	grouped := make(map[string][]manual.Section)
+	layers := make(map[string]bool)
 	for _, s := range sections {
 		if _, exists := grouped[s.Group]; !exists {
 			groupOrder = append(groupOrder, s.Group)
 		}
 		grouped[s.Group] = append(grouped[s.Group], s)
+		layers[s.Layer] = true
 	}
+	showLayer := len(layers) > 1
 
 	if len(groupOrder) == 0 {
 		fmt.Fprintln(w, "No sections found.")
@@ -31,17 +39,70 @@ func PrintSectionList(w io.Writer, sections []manual.Section) {
 		if i > 0 {
 			fmt.Fprintln(w)
 		}
-		fmt.Fprintf(w, "%s sections:\n", capitalize(g))
+		fmt.Fprintln(w, groupHeading(g, groups[g]))
 		for _, s := range grouped[g] {
-			title := s.Title
-			if title == "" {
-				title = s.Name
-			}
-			fmt.Fprintf(w, "  %-16s %s\n", s.Name, title)
+			fmt.Fprintln(w, sectionLine(s, showLayer, fields))
 		}
 	}
 }
 
+// groupHeading formats the line introducing a group in PrintSectionList.
+func groupHeading(group string, meta fs.GroupMeta) string {
+	title := meta.Title
+	if title == "" {
+		title = capitalize(group) + " sections"
+	}
+	if meta.Icon != "" {
+		title = meta.Icon + " " + title
+	}
+	if meta.Description != "" {
+		return title + ":  " + meta.Description
+	}
+	return title + ":"
+}
+
+// sectionLine formats one row of PrintSectionList.
+func sectionLine(s manual.Section, showLayer bool, fields []string) string {
+	title := s.Title
+	if title == "" {
+		title = s.Name
+	}
+
+	// Multi-line values are cut to their first line to keep one row per section.
+	var extra []string
+	if s.Description != "" {
+		extra = append(extra, firstLine(s.Description))
+	}
+	for _, f := range fields {
+		if v, ok := s.Field(f); ok {
+			extra = append(extra, f+"="+firstLine(v))
+		}
+	}
+	if showLayer {
+		extra = append(extra, "["+s.Layer+"]")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "  %-16s %s", s.Name, title)
+	if len(extra) > 0 {
+		fmt.Fprintf(&b, "%s  %s", strings.Repeat(" ", max(0, 24-len(title))), strings.Join(extra, "  "))
+	}
+	return b.String()
+}
+
+// PrintTagList writes tags with their section counts to w.
+func PrintTagList(w io.Writer, tags []manual.TagCount) {
+	if len(tags) == 0 {
+		fmt.Fprintln(w, "No tags found.")
+		return
+	}
+
+	fmt.Fprintln(w, "Tags:")
+	for _, t := range tags {
+		fmt.Fprintf(w, "  %-16s %d\n", t.Tag, t.Count)
+	}
+}
+
 // PrintTemplateList writes the available templates to w.
 func PrintTemplateList(w io.Writer, templates []manual.Template) {
 	fmt.Fprintln(w, "Available templates:")
@@ -63,6 +124,16 @@ func PrintTemplateList(w io.Writer, templates []manual.Template) {
 	fmt.Fprintln(w, "  pm init --template <path.json>   Use a custom template file")
 }
 
+// firstLine returns the first line of s, marking with "…" that more follows.
+func firstLine(s string) string {
+	line, rest, found := strings.Cut(strings.TrimSpace(s), "\n")
+	line = strings.TrimSpace(line)
+	if found && strings.TrimSpace(rest) != "" {
+		return line + " …"
+	}
+	return line
+}
+
 func capitalize(s string) string {
 	if s == "" {
 		return s
@@ -70,20 +141,99 @@ func capitalize(s string) string {
 	return strings.ToUpper(s[:1]) + s[1:]
 }
 
-// PrintSearchResults writes search results in grep-like format to w.
+// PrintSearchResults writes search results grouped by section to w.
+// Each section is introduced by its title and path; each hit shows its line
+// number, context lines (marked with "-") and, when it changes, the heading path.
 func PrintSearchResults(w io.Writer, results []fs.SearchResult) {
 	if len(results) == 0 {
 		fmt.Fprintln(w, "No matches found.")
 		return
 	}
 
+	var (
+		section     string // layer + file of the section being printed
+		breadcrumbs string
+		lastLine    int // last line number printed in the current section
+	)
 	for _, r := range results {
-		fmt.Fprintf(w, "%s:%d: %s\n", r.File, r.Line, r.Content)
+		if key := r.Layer + ":" + r.File; key != section {
+			if section != "" {
+				fmt.Fprintln(w)
+			}
+			section, breadcrumbs, lastLine = key, "", 0
+			fmt.Fprintln(w, searchHeader(r))
+		}
+
+		first := r.Line - len(r.Before)
+		if lastLine > 0 && first > lastLine+1 && (len(r.Before) > 0 || len(r.After) > 0) {
+			fmt.Fprintln(w, "    --")
+		}
+
+		if path := strings.Join(r.Headings, " > "); path != breadcrumbs && path != "" {
+			breadcrumbs = path
+			fmt.Fprintf(w, "  %s\n", path)
+		}
+		for i, line := range r.Before {
+			if n := first + i; n > lastLine {
+				fmt.Fprintf(w, "    %d- %s\n", n, line)
+			}
+		}
+		if r.Line > lastLine {
+			fmt.Fprintf(w, "    %d: %s\n", r.Line, r.Content)
+		}
+		lastLine = max(lastLine, r.Line)
+		for i, line := range r.After {
+			if n := r.Line + 1 + i; n > lastLine {
+				fmt.Fprintf(w, "    %d- %s\n", n, line)
+				lastLine = n
+			}
+		}
 	}
 
 	fmt.Fprintf(w, "\n%d match(es) found.\n", len(results))
 }
 
+// searchHeader formats the line introducing a section's search hits.
+func searchHeader(r fs.SearchResult) string {
+	header := r.File
+	if r.Title != "" {
+		header = fmt.Sprintf("%s (%s)", r.Title, r.File)
+	}
+	if r.Layer != "" {
+		header += " [" + r.Layer + "]"
+	}
+	if r.Score > 0 {
+		header += fmt.Sprintf(" score %.2f", r.Score)
+	}
+	return header
+}
+
+// PrintTOC writes a section's heading outline to w, indented by level, with
+// the slug to pass to "pm open <section>#<slug>" after each heading.
+func PrintTOC(w io.Writer, s manual.Section, headings []manual.Heading) {
+	if len(headings) == 0 {
+		fmt.Fprintf(w, "%s/%s has no headings.\n", s.Group, s.Name)
+		return
+	}
+
+	top := headings[0].Level
+	for _, h := range headings {
+		top = min(top, h.Level)
+	}
+
+	lines := make([]string, len(headings))
+	width := 0
+	for i, h := range headings {
+		lines[i] = strings.Repeat("  ", h.Level-top) + h.Text
+		width = max(width, len(lines[i]))
+	}
+	width = min(width, 48)
+
+	for i, h := range headings {
+		fmt.Fprintf(w, "%-*s  #%s\n", width, lines[i], h.Slug)
+	}
+}
+
 // PrintSectionContent writes a section's content to w.
 func PrintSectionContent(w io.Writer, s manual.Section) {
 	header := s.Title
@@ -97,10 +247,10 @@ func PrintSectionContent(w io.Writer, s manual.Section) {
 }
 
 // PrintProjectSummary writes a brief project summary with available sections.
-func PrintProjectSummary(w io.Writer, sections []manual.Section) {
+func PrintProjectSummary(w io.Writer, sections []manual.Section, groups map[string]fs.GroupMeta) {
 	fmt.Fprintln(w, "Project manual (.pm/) detected.")
 	fmt.Fprintln(w)
-	PrintSectionList(w, sections)
+	PrintSectionList(w, sections, groups)
 	fmt.Fprintln(w)
 	fmt.Fprintln(w, "Usage:")
 	fmt.Fprintln(w, "  pm open <section>    Open a section")
@@ -108,8 +258,35 @@ func PrintProjectSummary(w io.Writer, sections []manual.Section) {
 	fmt.Fprintln(w, "  pm search <keyword>  Search across sections")
 }
 
-// PrintNoPMDir writes a message when no .pm/ directory is found.
-func PrintNoPMDir(w io.Writer) {
-	fmt.Fprintln(w, "No .pm/ directory found in the current directory.")
-	fmt.Fprintln(w, "Run 'pm init' to create one.")
+// PrintError writes an error and, where useful, a hint on how to recover to w.
+func PrintError(w io.Writer, err error) {
+	fmt.Fprintf(w, "Error: %v\n", err)
+
+	var (
+		notFound  *fs.SectionNotFoundError
+		noHeading *manual.HeadingNotFoundError
+		ambiguous *fs.AmbiguousSectionError
+		exists    *fs.SectionExistsError
+		noGroup   *fs.GroupNotFoundError
+		missing   *manual.MissingParamsError
+		noSession *fs.SessionNotFoundError
+	)
+	switch {
+	case errors.Is(err, fs.ErrNoManual):
+		fmt.Fprintln(w, "Run 'pm init' to create one.")
+	case errors.As(err, &notFound):
+		fmt.Fprintln(w, "Run 'pm list' to see available sections.")
+	case errors.As(err, &missing):
+		fmt.Fprintln(w, "Pass values with --set name=value.")
+	case errors.As(err, &noGroup):
+		fmt.Fprintln(w, "Run 'pm list' to see available groups.")
+	case errors.As(err, &noHeading):
+		fmt.Fprintln(w, "Run 'pm toc <section>' to see its headings.")
+	case errors.As(err, &noSession):
+		fmt.Fprintln(w, "Run 'pm sessions list' to see recorded sessions.")
+	case errors.As(err, &ambiguous):
+		fmt.Fprintln(w, "Use the group/name form to pick one.")
+	case errors.As(err, &exists):
+		fmt.Fprintf(w, "Run 'pm edit %s/%s' to change it, or choose another name.\n", exists.Existing.Group, exists.Existing.Name)
+	}
 }
@@ -0,0 +1,20 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/hojooneum/pm/internal/manual"
+)
+
+func TestSectionLine_MultilineValues(t *testing.T) {
+	s := manual.Section{
+		Name:        "deploy",
+		Title:       "Deploy",
+		Description: "Roll out a release.\nCovers canaries and rollbacks.\n",
+		Meta:        map[string]any{"owner": "platform\nteam"},
+	}
+	want := "  deploy           Deploy                    Roll out a release. …  Owner=platform …"
+	if got := sectionLine(s, false, []string{"Owner"}); got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"regexp"
+	"strings"
+)
+
+// language describes just enough of a language's lexical syntax for
+// line-by-line highlighting of code blocks.
+type language struct {
+	comments   []string // line comment markers
+	keywords   map[string]bool
+	foldCase   bool // keywords are case-insensitive (SQL)
+	variables  bool // $NAME and ${NAME} expansions (shells)
+	keys       bool // "key:" at the start of a line (YAML)
+	quotedKeys bool // "key": (JSON)
+}
+
+func words(s string) map[string]bool {
+	m := make(map[string]bool)
+	for _, w := range strings.Fields(s) {
+		m[w] = true
+	}
+	return m
+}
+
+var (
+	shellLang = &language{
+		comments:  []string{"#"},
+		keywords:  words("if then else elif fi for while until do done case esac in function return export local set unset exit sudo"),
+		variables: true,
+	}
+	goLang = &language{
+		comments: []string{"//"},
+		keywords: words("break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var nil true false"),
+	}
+	pythonLang = &language{
+		comments: []string{"#"},
+		keywords: words("and as assert async await break class continue def del elif else except finally for from global if import in is lambda not or pass raise return try while with yield None True False"),
+	}
+	jsLang = &language{
+		comments: []string{"//"},
+		keywords: words("async await break case catch class const continue default delete do else export extends finally for function if import in instanceof let new return switch this throw try typeof var void while yield null undefined true false"),
+	}
+	sqlLang = &language{
+		comments: []string{"--"},
+		keywords: words("select from where and or not insert into values update set delete create table index drop alter add join left right inner outer on group by order having limit as null is in like begin commit rollback grant vacuum analyze"),
+		foldCase: true,
+	}
+	yamlLang = &language{
+		comments: []string{"#"},
+		keywords: words("true false null yes no on off"),
+		keys:     true,
+	}
+	jsonLang = &language{
+		keywords:   words("true false null"),
+		quotedKeys: true,
+	}
+)
+
+// languages maps code fence info strings to their syntax.
+var languages = map[string]*language{
+	"sh": shellLang, "bash": shellLang, "shell": shellLang, "zsh": shellLang, "console": shellLang,
+	"go": goLang, "golang": goLang,
+	"python": pythonLang, "py": pythonLang,
+	"js": jsLang, "javascript": jsLang, "ts": jsLang, "typescript": jsLang,
+	"sql": sqlLang, "psql": sqlLang,
+	"yaml": yamlLang, "yml": yamlLang,
+	"json": jsonLang,
+}
+
+var yamlKey = regexp.MustCompile(`^(\s*(?:-\s+)?)([^\s#:][^#:]*?)(:)(\s|$)`)
+
+// highlight colors one line of code in the given language. Lines in unknown
+// languages are returned unchanged.
+func highlight(lang, line string) string {
+	l := languages[lang]
+	if l == nil {
+		return line
+	}
+
+	var b strings.Builder
+	if l.keys {
+		if m := yamlKey.FindStringSubmatchIndex(line); m != nil {
+			b.WriteString(line[:m[3]])
+			b.WriteString(colorCyan + line[m[4]:m[5]] + sgrFgOff)
+			line = line[m[5]:]
+		}
+	}
+
+	for i := 0; i < len(line); {
+		rest := line[i:]
+		c := rest[0]
+
+		if l.isComment(line, i) {
+			b.WriteString(colorGray + rest + sgrFgOff)
+			break
+		}
+
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			end := closingQuote(rest)
+			color := colorGreen
+			if l.quotedKeys && strings.HasPrefix(strings.TrimLeft(rest[end:], " "), ":") {
+				color = colorCyan
+			}
+			b.WriteString(color + rest[:end] + sgrFgOff)
+			i += end
+			continue
+
+		case c == '$' && l.variables && len(rest) > 1:
+			if n := variableLen(rest); n > 1 {
+				b.WriteString(colorMagenta + rest[:n] + sgrFgOff)
+				i += n
+				continue
+			}
+
+		case isWordByte(c):
+			n := 1
+			for n < len(rest) && (isWordByte(rest[n]) || rest[n] == '-' && !l.foldCase) {
+				n++
+			}
+			word := rest[:n]
+			if i > 0 && isWordByte(line[i-1]) {
+				b.WriteString(word)
+			} else {
+				b.WriteString(l.colorWord(word))
+			}
+			i += n
+			continue
+		}
+
+		b.WriteByte(c)
+		i++
+	}
+	return b.String()
+}
+
+// isComment reports whether a line comment starts at line[i]. A shell-style
+// "#" only starts a comment at the start of the line or after whitespace.
+func (l *language) isComment(line string, i int) bool {
+	for _, m := range l.comments {
+		if strings.HasPrefix(line[i:], m) && (m != "#" || i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *language) colorWord(word string) string {
+	key := word
+	if l.foldCase {
+		key = strings.ToLower(word)
+	}
+	switch {
+	case l.keywords[key]:
+		return colorBlue + word + sgrFgOff
+	case word[0] >= '0' && word[0] <= '9':
+		return colorYellow + word + sgrFgOff
+	default:
+		return word
+	}
+}
+
+// closingQuote returns the length of the quoted string at the start of s,
+// including both quotes, or len(s) if it is not closed on this line.
+func closingQuote(s string) int {
+	q := s[0]
+	for k := 1; k < len(s); k++ {
+		switch s[k] {
+		case '\\':
+			if q != '\'' {
+				k++
+			}
+		case q:
+			return k + 1
+		}
+	}
+	return len(s)
+}
+
+// variableLen returns the length of the $NAME, ${...} or $1 expansion at the start of s.
+func variableLen(s string) int {
+	if s[1] == '{' {
+		if end := strings.IndexByte(s, '}'); end > 0 {
+			return end + 1
+		}
+		return len(s)
+	}
+	n := 1
+	for n < len(s) && (isWordByte(s[n]) && s[n] < 0x80) {
+		n++
+	}
+	if n == 1 && strings.IndexByte("?#@*!$-", s[1]) >= 0 {
+		return 2
+	}
+	return n
+}
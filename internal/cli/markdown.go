@@ -0,0 +1,616 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hojooneum/pm/internal/manual"
+)
+
+// MarkdownOptions controls how RenderMarkdown formats a body.
+type MarkdownOptions struct {
+	Width int  // wrap paragraphs and list items to this many columns; 0 disables wrapping
+	Color bool // emit ANSI styles; without it only the layout is rendered
+}
+
+// SGR codes used by the renderer. Each style is turned off with its own reset
+// code rather than a full reset, so styles can nest.
+const (
+	sgrBold      = "\x1b[1m"
+	sgrBoldOff   = "\x1b[22m"
+	sgrDim       = "\x1b[2m"
+	sgrItalic    = "\x1b[3m"
+	sgrItalicOff = "\x1b[23m"
+	sgrUnder     = "\x1b[4m"
+	sgrUnderOff  = "\x1b[24m"
+	sgrStrike    = "\x1b[9m"
+	sgrStrikeOff = "\x1b[29m"
+	sgrFgOff     = "\x1b[39m"
+	sgrReset     = "\x1b[0m"
+)
+
+// Foreground colors.
+const (
+	colorGreen   = "\x1b[32m"
+	colorYellow  = "\x1b[33m"
+	colorBlue    = "\x1b[34m"
+	colorMagenta = "\x1b[35m"
+	colorCyan    = "\x1b[36m"
+	colorGray    = "\x1b[90m"
+)
+
+// PrintSectionRendered writes a section to w with its markdown body rendered
+// for the terminal (see RenderMarkdown), under a one-line group/name header.
+func PrintSectionRendered(w io.Writer, s manual.Section, opts MarkdownOptions) {
+	r := renderer{opts: opts}
+	fmt.Fprintln(w, r.style(sgrDim, sgrReset, "["+s.Group+"/"+s.Name+"]"))
+	fmt.Fprintln(w)
+	RenderMarkdown(w, s.Body, opts)
+}
+
+// PrintStep writes a runbook step: a "Step n/total" header with the headings
+// enclosing the block and notes on its annotations, followed by its code,
+// indented and highlighted as in RenderMarkdown.
+func PrintStep(w io.Writer, n, total int, b manual.CodeBlock, opts MarkdownOptions) {
+	r := renderer{opts: opts}
+	header := fmt.Sprintf("Step %d/%d", n, total)
+	if len(b.Path) > 0 {
+		header += ": " + strings.Join(b.Path, " > ")
+	}
+	fmt.Fprintln(w, r.style(sgrBold, sgrReset, header))
+	if notes := stepNotes(b); len(notes) > 0 {
+		fmt.Fprintln(w, "  "+r.style(colorYellow, sgrFgOff, "("+strings.Join(notes, ", ")+")"))
+	}
+	for _, line := range strings.Split(strings.TrimRight(b.Code, "\n"), "\n") {
+		code := strings.ReplaceAll(line, "\t", "    ")
+		if opts.Color {
+			code = highlight(b.Lang, code)
+		}
+		fmt.Fprintln(w, "    "+code)
+	}
+}
+
+// stepNotes describes a block's annotations, in the order of manual.Annotations.
+func stepNotes(b manual.CodeBlock) []string {
+	var notes []string
+	for _, a := range manual.Annotations {
+		if !b.Has(a) {
+			continue
+		}
+		switch a {
+		case "allow-fail":
+			notes = append(notes, "allowed to fail")
+		case "destructive":
+			notes = append(notes, "destructive")
+		case "confirm":
+			notes = append(notes, "needs typed confirmation")
+		case "env":
+			notes = append(notes, "only in env "+strings.Join(b.Envs(), " or "))
+		}
+	}
+	return notes
+}
+
+// RenderMarkdown writes a markdown body to w formatted for a terminal: styled
+// headings and emphasis, bulleted lists and checkboxes, aligned tables,
+// highlighted code blocks and links followed by their URL. Paragraphs, list
+// items and quotes are wrapped to opts.Width. Without opts.Color the same
+// layout is produced as plain text, and headings keep their "#" markers.
+func RenderMarkdown(w io.Writer, body string, opts MarkdownOptions) {
+	r := renderer{opts: opts}
+	for _, line := range r.render(strings.Split(strings.TrimRight(body, "\n"), "\n")) {
+		fmt.Fprintln(w, line)
+	}
+}
+
+var (
+	ruleRe   = regexp.MustCompile(`^\s{0,3}((\*\s*){3,}|(-\s*){3,}|(_\s*){3,})$`)
+	listRe   = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(\[[ xX]\]\s+)?(.*)$`)
+	tableSep = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	ansiRe   = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+)
+
+type renderer struct {
+	opts MarkdownOptions
+	out  []string
+}
+
+// style wraps s in on/off codes when color is enabled.
+func (r *renderer) style(on, off, s string) string {
+	if !r.opts.Color || s == "" {
+		return s
+	}
+	return on + s + off
+}
+
+func (r *renderer) emit(lines ...string) {
+	r.out = append(r.out, lines...)
+}
+
+// blank emits an empty line unless the output already ends with one.
+func (r *renderer) blank() {
+	if n := len(r.out); n > 0 && r.out[n-1] != "" {
+		r.out = append(r.out, "")
+	}
+}
+
+func (r *renderer) render(lines []string) []string {
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			r.emit(r.wrap(r.inline(strings.Join(para, " ")), "", "")...)
+			para = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+			r.blank()
+
+		case manual.FenceMarker(trimmed) != "":
+			flush()
+			i = r.codeBlock(lines, i)
+
+		case strings.HasPrefix(trimmed, "<!--"):
+			flush()
+			i = r.comment(lines, i)
+
+		case isHeading(line):
+			flush()
+			h, _ := manual.ParseHeadingLine(line)
+			r.heading(h.Level, h.Text)
+
+		case ruleRe.MatchString(line):
+			flush()
+			r.emit(r.style(sgrDim, sgrReset, strings.Repeat("─", r.ruleWidth())))
+
+		case strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && tableSep.MatchString(lines[i+1]):
+			flush()
+			i = r.table(lines, i)
+
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			text := strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+			bar := r.style(colorGray, sgrFgOff, "│ ")
+			r.emit(r.wrapStyled(r.inline(text), bar, bar, sgrItalic, sgrItalicOff)...)
+
+		case listRe.MatchString(line):
+			flush()
+			i = r.listItem(lines, i)
+
+		default:
+			para = append(para, trimmed)
+		}
+	}
+	flush()
+
+	for len(r.out) > 0 && r.out[len(r.out)-1] == "" {
+		r.out = r.out[:len(r.out)-1]
+	}
+	return r.out
+}
+
+// isHeading reports whether line is an ATX heading.
+func isHeading(line string) bool {
+	_, ok := manual.ParseHeadingLine(line)
+	return ok
+}
+
+func (r *renderer) heading(level int, text string) {
+	text = r.inline(text)
+	if !r.opts.Color {
+		r.emit(strings.Repeat("#", level) + " " + text)
+		return
+	}
+	switch level {
+	case 1:
+		r.emit(sgrBold + sgrUnder + colorMagenta + text + sgrReset)
+	case 2:
+		r.emit(sgrBold + colorCyan + text + sgrReset)
+	case 3:
+		r.emit(sgrBold + colorBlue + text + sgrReset)
+	default:
+		r.emit(sgrBold + text + sgrReset)
+	}
+}
+
+// codeBlock renders the fenced block opening at lines[i] and returns the index
+// of its closing fence (or the last line if it is never closed).
+func (r *renderer) codeBlock(lines []string, i int) int {
+	var sc manual.Scanner
+	sc.Scan(lines[i])
+	lang := ""
+	if info := strings.Fields(strings.TrimPrefix(strings.TrimSpace(lines[i]), sc.Fence())); len(info) > 0 {
+		lang = strings.ToLower(info[0])
+	}
+
+	j := i + 1
+	for ; j < len(lines); j++ {
+		if sc.Scan(lines[j]) == manual.FenceClose {
+			break
+		}
+		code := strings.ReplaceAll(lines[j], "\t", "    ")
+		if r.opts.Color {
+			code = highlight(lang, code)
+		}
+		r.emit("    " + code)
+	}
+	return j
+}
+
+// comment renders an HTML comment block (the templates' TODO notes) dimmed
+// and returns the index of its last line.
+func (r *renderer) comment(lines []string, i int) int {
+	j := i
+	for j < len(lines)-1 && !strings.Contains(lines[j], "-->") {
+		j++
+	}
+	for _, l := range lines[i : j+1] {
+		r.emit(r.wrapStyled(strings.TrimSpace(l), "", "", sgrDim, sgrReset)...)
+	}
+	return j
+}
+
+// listItem renders the list item at lines[i], including indented continuation
+// lines, and returns the index of its last line.
+func (r *renderer) listItem(lines []string, i int) int {
+	m := listRe.FindStringSubmatch(lines[i])
+	indent, marker, box, text := m[1], m[2], strings.TrimSpace(m[3]), m[4]
+
+	j := i + 1
+	for ; j < len(lines); j++ {
+		next := lines[j]
+		t := strings.TrimSpace(next)
+		if t == "" || listRe.MatchString(next) || !strings.HasPrefix(next, " ") || manual.FenceMarker(t) != "" {
+			break
+		}
+		text += " " + t
+	}
+
+	bullet, on, off := marker, "", ""
+	switch {
+	case box == "[ ]":
+		bullet = r.style(colorYellow, sgrFgOff, "☐")
+	case box != "":
+		bullet = r.style(colorGreen, sgrFgOff, "☑")
+		on, off = sgrDim, sgrReset
+	case !unicode.IsDigit(rune(marker[0])):
+		bullet = r.style(colorCyan, sgrFgOff, "•")
+	}
+
+	prefix := "  " + strings.Repeat(" ", len(indent))
+	first := prefix + bullet + " "
+	rest := prefix + strings.Repeat(" ", visibleWidth(bullet)+1)
+	r.emit(r.wrapStyled(r.inline(text), first, rest, on, off)...)
+	return j - 1
+}
+
+// table renders the pipe table starting at lines[i] with aligned columns and
+// returns the index of its last row.
+func (r *renderer) table(lines []string, i int) int {
+	header := splitRow(lines[i])
+	aligns := splitRow(lines[i+1])
+	rows := [][]string{header}
+
+	j := i + 2
+	for ; j < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[j]), "|"); j++ {
+		rows = append(rows, splitRow(lines[j]))
+	}
+
+	cols := len(header)
+	widths := make([]int, cols)
+	for ri, row := range rows {
+		for c := range row {
+			if c < cols {
+				row[c] = r.inline(row[c])
+				widths[c] = max(widths[c], visibleWidth(row[c]))
+			}
+		}
+		rows[ri] = row
+	}
+
+	sep := r.style(colorGray, sgrFgOff, " │ ")
+	for ri, row := range rows {
+		cells := make([]string, cols)
+		for c := range cells {
+			cell := ""
+			if c < len(row) {
+				cell = row[c]
+			}
+			align := ""
+			if c < len(aligns) {
+				align = aligns[c]
+			}
+			cell = pad(cell, widths[c], align)
+			if ri == 0 {
+				cell = r.style(sgrBold, sgrBoldOff, cell)
+			}
+			cells[c] = cell
+		}
+		r.emit(strings.TrimRight("  "+strings.Join(cells, sep), " "))
+
+		if ri == 0 {
+			parts := make([]string, cols)
+			for c, w := range widths {
+				parts[c] = strings.Repeat("─", w)
+			}
+			r.emit(r.style(colorGray, sgrFgOff, "  "+strings.Join(parts, "─┼─")))
+		}
+	}
+	return j - 1
+}
+
+// splitRow splits a table row into trimmed cells, honoring escaped pipes.
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for k := 0; k < len(line); k++ {
+		switch {
+		case line[k] == '\\' && k+1 < len(line) && line[k+1] == '|':
+			cell.WriteByte('|')
+			k++
+		case line[k] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[k])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// pad pads a cell to width according to its separator cell (":--", ":-:" or "--:").
+func pad(s string, width int, align string) string {
+	gap := width - visibleWidth(s)
+	if gap <= 0 {
+		return s
+	}
+	switch {
+	case strings.HasPrefix(align, ":") && strings.HasSuffix(align, ":"):
+		return strings.Repeat(" ", gap/2) + s + strings.Repeat(" ", gap-gap/2)
+	case strings.HasSuffix(align, ":"):
+		return strings.Repeat(" ", gap) + s
+	default:
+		return s + strings.Repeat(" ", gap)
+	}
+}
+
+func (r *renderer) ruleWidth() int {
+	if r.opts.Width > 0 {
+		return r.opts.Width
+	}
+	return 40
+}
+
+// wrap word-wraps styled text to the renderer's width. first prefixes the
+// first line and rest the others; both count towards the width.
+func (r *renderer) wrap(text, first, rest string) []string {
+	width := r.opts.Width
+	if width <= 0 {
+		return []string{first + text}
+	}
+
+	var lines []string
+	line, lineWidth := first, visibleWidth(first)
+	empty := true
+	for _, word := range strings.Fields(text) {
+		ww := visibleWidth(word)
+		if !empty && lineWidth+1+ww > width {
+			lines = append(lines, line)
+			line, lineWidth, empty = rest, visibleWidth(rest), true
+		}
+		if !empty {
+			line += " "
+			lineWidth++
+		}
+		line += word
+		lineWidth += ww
+		empty = false
+	}
+	return append(lines, line)
+}
+
+// wrapStyled wraps text like wrap and applies a style to each line's text,
+// leaving the prefixes unstyled.
+func (r *renderer) wrapStyled(text, first, rest, on, off string) []string {
+	lines := r.wrap(text, first, rest)
+	for i, l := range lines {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		lines[i] = prefix + r.style(on, off, strings.TrimPrefix(l, prefix))
+	}
+	return lines
+}
+
+// DisplayLines returns how many terminal rows s takes when printed on a
+// terminal width columns wide, counting lines the terminal wraps.
+func DisplayLines(s string, width int) int {
+	n := 0
+	for _, line := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+		w := visibleWidth(line)
+		if width <= 0 || w <= width {
+			n++
+			continue
+		}
+		n += (w + width - 1) / width
+	}
+	return n
+}
+
+// visibleWidth returns the number of columns s takes, ignoring ANSI escapes.
+func visibleWidth(s string) int {
+	return utf8.RuneCountInString(ansiRe.ReplaceAllString(s, ""))
+}
+
+// inline renders inline markdown: code spans, bold, italics, strikethrough,
+// links, images, autolinks, HTML comments and backslash escapes. Emphasis
+// markers are only consumed when a closing marker follows, and "_" only at
+// word boundaries so snake_case names are left alone.
+func (r *renderer) inline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		rest := s[i:]
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && strings.ContainsRune("\\`*_[]()#+-.!|~<>", rune(rest[1])):
+			b.WriteByte(rest[1])
+			i += 2
+			continue
+
+		case rest[0] == '`':
+			ticks := len(rest) - len(strings.TrimLeft(rest, "`"))
+			if end := strings.Index(rest[ticks:], rest[:ticks]); end >= 0 {
+				code := strings.TrimSpace(rest[ticks : ticks+end])
+				if r.opts.Color {
+					b.WriteString(colorCyan + code + sgrFgOff)
+				} else {
+					b.WriteString("`" + code + "`")
+				}
+				i += 2*ticks + end
+				continue
+			}
+
+		case strings.HasPrefix(rest, "<!--"):
+			if end := strings.Index(rest, "-->"); end >= 0 {
+				b.WriteString(r.style(sgrDim, sgrReset, rest[:end+3]))
+				i += end + 3
+				continue
+			}
+
+		case rest[0] == '<' && (strings.HasPrefix(rest, "<http://") || strings.HasPrefix(rest, "<https://")):
+			if end := strings.IndexByte(rest, '>'); end >= 0 {
+				b.WriteString(r.style(sgrUnder+colorBlue, sgrUnderOff+sgrFgOff, rest[1:end]))
+				i += end + 1
+				continue
+			}
+
+		case rest[0] == '[' || strings.HasPrefix(rest, "!["):
+			if text, url, n, ok := parseLink(rest); ok {
+				b.WriteString(r.link(text, url, rest[0] == '!'))
+				i += n
+				continue
+			}
+
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if inner, n, ok := delimited(s, i, rest[:2]); ok {
+				b.WriteString(r.style(sgrBold, sgrBoldOff, r.inline(inner)))
+				i += n
+				continue
+			}
+
+		case strings.HasPrefix(rest, "~~"):
+			if inner, n, ok := delimited(s, i, "~~"); ok {
+				b.WriteString(r.style(sgrStrike, sgrStrikeOff, r.inline(inner)))
+				i += n
+				continue
+			}
+
+		case rest[0] == '*' || rest[0] == '_':
+			if inner, n, ok := delimited(s, i, rest[:1]); ok {
+				b.WriteString(r.style(sgrItalic, sgrItalicOff, r.inline(inner)))
+				i += n
+				continue
+			}
+		}
+
+		_, size := utf8.DecodeRuneInString(rest)
+		b.WriteString(rest[:size])
+		i += size
+	}
+	return b.String()
+}
+
+// link renders a link as its text followed by the URL, or an image as its alt text.
+func (r *renderer) link(text, url string, image bool) string {
+	if image {
+		return r.style(sgrDim, sgrReset, "[image: "+text+"]")
+	}
+	label := r.inline(text)
+	if text == url || text == "" {
+		return r.style(sgrUnder+colorBlue, sgrUnderOff+sgrFgOff, url)
+	}
+	return r.style(sgrUnder+colorBlue, sgrUnderOff+sgrFgOff, label) + " " + r.style(colorGray, sgrFgOff, "("+url+")")
+}
+
+// parseLink parses "[text](url)" or "![alt](url)" at the start of s and
+// returns the number of bytes consumed.
+func parseLink(s string) (text, url string, n int, ok bool) {
+	start := 1
+	if s[0] == '!' {
+		start = 2
+	}
+	depth := 0
+	for k := start; k < len(s); k++ {
+		switch s[k] {
+		case '[':
+			depth++
+		case ']':
+			if depth > 0 {
+				depth--
+				continue
+			}
+			if k+1 >= len(s) || s[k+1] != '(' {
+				return "", "", 0, false
+			}
+			end := strings.IndexByte(s[k+2:], ')')
+			if end < 0 {
+				return "", "", 0, false
+			}
+			url = strings.TrimSpace(s[k+2 : k+2+end])
+			if sp := strings.IndexAny(url, " \t"); sp >= 0 {
+				url = url[:sp] // drop a link title
+			}
+			return s[start:k], url, k + 3 + end, true
+		}
+	}
+	return "", "", 0, false
+}
+
+// delimited finds the emphasis span opening with marker at s[i]. It returns
+// the inner text and the number of bytes consumed including both markers.
+func delimited(s string, i int, marker string) (string, int, bool) {
+	open := i + len(marker)
+	if open >= len(s) || s[open] == ' ' {
+		return "", 0, false
+	}
+	underscore := marker[0] == '_'
+	if underscore && i > 0 && isWordByte(s[i-1]) {
+		return "", 0, false
+	}
+
+	for k := open + 1; k+len(marker) <= len(s); k++ {
+		if s[k:k+len(marker)] != marker || s[k-1] == ' ' {
+			continue
+		}
+		// A single "*" must not be half of a "**".
+		if len(marker) == 1 && k+1 < len(s) && s[k+1] == marker[0] {
+			k++
+			continue
+		}
+		if underscore && k+len(marker) < len(s) && isWordByte(s[k+len(marker)]) {
+			continue
+		}
+		return s[open:k], k + len(marker) - i, true
+	}
+	return "", 0, false
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
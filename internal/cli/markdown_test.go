@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hojooneum/pm/internal/manual"
+)
+
+func renderPlain(body string, width int) string {
+	var buf bytes.Buffer
+	RenderMarkdown(&buf, body, MarkdownOptions{Width: width})
+	return buf.String()
+}
+
+func TestRenderMarkdown_Plain(t *testing.T) {
+	body := "# Title\n\nSome **bold** and _it_ text with `code` and [docs](https://example.com).\n\n" +
+		"- [ ] todo\n- [x] done\n- item\n1. first\n\n<!-- TODO: fill in -->\n\n---\n"
+
+	want := strings.Join([]string{
+		"# Title",
+		"",
+		"Some bold and it text with `code` and docs (https://example.com).",
+		"",
+		"  ☐ todo",
+		"  ☑ done",
+		"  • item",
+		"  1. first",
+		"",
+		"<!-- TODO: fill in -->",
+		"",
+		strings.Repeat("─", 40),
+		"",
+	}, "\n")
+	if got := renderPlain(body, 0); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderMarkdown_NoColorWithoutOption(t *testing.T) {
+	got := renderPlain("# T\n\n**b** `c`\n\n```bash\necho $HOME\n```\n", 80)
+	if strings.Contains(got, "\x1b[") {
+		t.Errorf("expected no ANSI escapes, got %q", got)
+	}
+}
+
+func TestRenderMarkdown_Color(t *testing.T) {
+	var buf bytes.Buffer
+	RenderMarkdown(&buf, "## Steps\n\nrun **now**\n", MarkdownOptions{Color: true})
+	got := buf.String()
+
+	for _, want := range []string{sgrBold + colorCyan + "Steps", sgrBold + "now" + sgrBoldOff} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %q", want, got)
+		}
+	}
+	if strings.Contains(got, "##") || strings.Contains(got, "**") {
+		t.Errorf("expected markdown markers to be consumed: %q", got)
+	}
+}
+
+func TestRenderMarkdown_Wrap(t *testing.T) {
+	got := renderPlain("one two three four five six seven\n\n- alpha beta gamma delta epsilon\n", 16)
+	want := "one two three\nfour five six\nseven\n\n  • alpha beta\n    gamma delta\n    epsilon\n"
+	if got != want {
+		t.Errorf("got:\n%q\nwant:\n%q", got, want)
+	}
+	for _, line := range strings.Split(strings.TrimRight(got, "\n"), "\n") {
+		if visibleWidth(line) > 16 {
+			t.Errorf("line exceeds width: %q", line)
+		}
+	}
+}
+
+func TestRenderMarkdown_Table(t *testing.T) {
+	got := renderPlain("| Target | Freq |\n|:--|--:|\n| Database | daily |\n| Files | 1 |\n", 0)
+	want := strings.Join([]string{
+		"  Target   │  Freq",
+		"  ─────────┼──────",
+		"  Database │ daily",
+		"  Files    │     1",
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderMarkdown_CodeBlock(t *testing.T) {
+	got := renderPlain("```bash\n# Restart\nkubectl rollout restart **x**\n```\n", 10)
+	want := "    # Restart\n    kubectl rollout restart **x**\n"
+	if got != want {
+		t.Errorf("code blocks should be indented verbatim, got:\n%q", got)
+	}
+}
+
+func TestInline_Emphasis(t *testing.T) {
+	r := renderer{}
+	tests := []struct {
+		in, want string
+	}{
+		{"snake_case_name stays", "snake_case_name stays"},
+		{"2 * 3 * 4", "2 * 3 * 4"},
+		{`\*literal\*`, "*literal*"},
+		{"~~old~~ new", "old new"},
+		{"![diagram](d.png)", "[image: diagram]"},
+		{"<https://example.com>", "https://example.com"},
+		{"[https://x.io](https://x.io)", "https://x.io"},
+	}
+	for _, tt := range tests {
+		if got := r.inline(tt.in); got != tt.want {
+			t.Errorf("inline(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDisplayLines(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  int
+	}{
+		{"a\nb\n", 80, 2},
+		{strings.Repeat("x", 25) + "\n", 10, 3},
+		{"\x1b[1m" + strings.Repeat("x", 10) + "\x1b[0m\n", 10, 1},
+		{"", 80, 1},
+	}
+	for _, tt := range tests {
+		if got := DisplayLines(tt.s, tt.width); got != tt.want {
+			t.Errorf("DisplayLines(%q, %d) = %d, want %d", tt.s, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	got := highlight("bash", `export FOO="bar" # note`)
+	for _, want := range []string{colorBlue + "export", colorGreen + `"bar"`, colorGray + "# note"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %q", want, got)
+		}
+	}
+
+	if got := highlight("bash", "echo a#b"); strings.Contains(got, colorGray) {
+		t.Errorf("# inside a word is not a comment: %q", got)
+	}
+	if got := highlight("yaml", "replicas: 3"); !strings.HasPrefix(got, colorCyan+"replicas") {
+		t.Errorf("expected YAML key to be highlighted: %q", got)
+	}
+	if got := highlight("unknown", "plain text"); got != "plain text" {
+		t.Errorf("unknown languages should be unchanged: %q", got)
+	}
+}
+
+func TestPrintStep(t *testing.T) {
+	b := manual.CodeBlock{
+		Lang:  "bash",
+		Attrs: map[string]string{"env": "prod,staging", "destructive": "", "allow-fail": ""},
+		Code:  "kubectl delete ns demo\n",
+		Path:  []string{"Cleanup", "Namespaces"},
+	}
+	var buf bytes.Buffer
+	PrintStep(&buf, 2, 3, b, MarkdownOptions{Width: 80})
+
+	want := "Step 2/3: Cleanup > Namespaces\n" +
+		"  (allowed to fail, destructive, only in env prod or staging)\n" +
+		"    kubectl delete ns demo\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderMarkdown_Headings(t *testing.T) {
+	body := "## Learn C#\n\n### Closed ###\n\n````md\n```\n# not a heading\n````\n"
+	want := "## Learn C#\n\n### Closed\n\n    ```\n    # not a heading\n"
+	if got := renderPlain(body, 80); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
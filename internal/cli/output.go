@@ -0,0 +1,298 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/hojooneum/pm/internal/fs"
+	"github.com/hojooneum/pm/internal/manual"
+	"go.yaml.in/yaml/v3"
+)
+
+// Format selects how command output is rendered.
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// ParseFormat validates an --output value. An empty string means text.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON, FormatYAML:
+		return Format(s), nil
+	}
+	return "", fmt.Errorf("unknown output format %q (available: text, json, yaml)", s)
+}
+
+// The types below are the machine-readable schema of pm's output.
+// Fields are only ever added, never renamed or removed.
+
+// SectionOutput describes one section (pm list, pm open).
+// Body is only set by pm open, and Heading when it opens a single heading.
+type SectionOutput struct {
+	Name        string         `json:"name" yaml:"name"`
+	Group       string         `json:"group" yaml:"group"`
+	Layer       string         `json:"layer,omitempty" yaml:"layer,omitempty"`
+	Title       string         `json:"title" yaml:"title"`
+	Description string         `json:"description,omitempty" yaml:"description,omitempty"`
+	Tags        []string       `json:"tags" yaml:"tags"`
+	Aliases     []string       `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Params      []ParamOutput  `json:"params,omitempty" yaml:"params,omitempty"`
+	Meta        map[string]any `json:"meta,omitempty" yaml:"meta,omitempty"`
+	Heading     string         `json:"heading,omitempty" yaml:"heading,omitempty"`
+	Body        string         `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// ParamOutput describes a runbook parameter declared by a section.
+// Default is omitted when the parameter is required.
+type ParamOutput struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Default     *string  `json:"default,omitempty" yaml:"default,omitempty"`
+	Values      []string `json:"values,omitempty" yaml:"values,omitempty"`
+	Secret      bool     `json:"secret,omitempty" yaml:"secret,omitempty"`
+}
+
+// SearchResultOutput describes one search hit (pm search).
+// Headings is the path of enclosing markdown headings, outermost first;
+// before and after hold context lines when requested with -B/-A/-C.
+// Score is only set by ranked searches.
+type SearchResultOutput struct {
+	Layer    string   `json:"layer,omitempty" yaml:"layer,omitempty"`
+	File     string   `json:"file" yaml:"file"`
+	Title    string   `json:"title,omitempty" yaml:"title,omitempty"`
+	Score    float64  `json:"score,omitempty" yaml:"score,omitempty"`
+	Line     int      `json:"line" yaml:"line"`
+	Content  string   `json:"content" yaml:"content"`
+	Headings []string `json:"headings" yaml:"headings"`
+	Before   []string `json:"before,omitempty" yaml:"before,omitempty"`
+	After    []string `json:"after,omitempty" yaml:"after,omitempty"`
+}
+
+// TagOutput describes one tag and its usage count (pm tags).
+type TagOutput struct {
+	Tag   string `json:"tag" yaml:"tag"`
+	Count int    `json:"count" yaml:"count"`
+}
+
+// HeadingOutput describes one heading of a section's outline (pm toc).
+type HeadingOutput struct {
+	Level int    `json:"level" yaml:"level"`
+	Text  string `json:"text" yaml:"text"`
+	Slug  string `json:"slug" yaml:"slug"`
+	Line  int    `json:"line" yaml:"line"`
+}
+
+// SessionOutput describes a recorded pm run (pm sessions). Status is empty
+// for a run that is still going or was killed. Events is only set by
+// pm sessions show.
+type SessionOutput struct {
+	ID      string            `json:"id" yaml:"id"`
+	Section string            `json:"section" yaml:"section"`
+	Heading string            `json:"heading,omitempty" yaml:"heading,omitempty"`
+	User    string            `json:"user,omitempty" yaml:"user,omitempty"`
+	Started time.Time         `json:"started" yaml:"started"`
+	Status  string            `json:"status" yaml:"status"`
+	Ran     int               `json:"ran" yaml:"ran"`
+	Skipped int               `json:"skipped" yaml:"skipped"`
+	Events  []fs.SessionEvent `json:"events,omitempty" yaml:"events,omitempty"`
+}
+
+// ChecklistOutput describes progress through a section's checklist
+// (pm check --status).
+type ChecklistOutput struct {
+	Section string                `json:"section" yaml:"section"`
+	Run     string                `json:"run,omitempty" yaml:"run,omitempty"`
+	Started *time.Time            `json:"started,omitempty" yaml:"started,omitempty"`
+	Done    int                   `json:"done" yaml:"done"`
+	Total   int                   `json:"total" yaml:"total"`
+	Items   []ChecklistItemOutput `json:"items" yaml:"items"`
+}
+
+// ChecklistItemOutput describes a checklist item. Time and User are set for
+// items ticked with pm check, not for those ticked in the markdown.
+type ChecklistItemOutput struct {
+	Text     string     `json:"text" yaml:"text"`
+	Headings []string   `json:"headings" yaml:"headings"`
+	Line     int        `json:"line" yaml:"line"`
+	Done     bool       `json:"done" yaml:"done"`
+	Time     *time.Time `json:"time,omitempty" yaml:"time,omitempty"`
+	User     string     `json:"user,omitempty" yaml:"user,omitempty"`
+}
+
+// TemplateOutput describes a template preset (pm init --list-templates).
+type TemplateOutput struct {
+	Name        string                  `json:"name" yaml:"name"`
+	Description string                  `json:"description,omitempty" yaml:"description,omitempty"`
+	Sections    []TemplateSectionOutput `json:"sections" yaml:"sections"`
+}
+
+// TemplateSectionOutput describes a section scaffolded by a template.
+type TemplateSectionOutput struct {
+	Name        string   `json:"name" yaml:"name"`
+	Group       string   `json:"group" yaml:"group"`
+	Title       string   `json:"title" yaml:"title"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Tags        []string `json:"tags" yaml:"tags"`
+}
+
+// NewSectionOutput converts a section. The body is included only when withBody is set.
+func NewSectionOutput(s manual.Section, withBody bool) SectionOutput {
+	out := SectionOutput{
+		Name:        s.Name,
+		Group:       s.Group,
+		Layer:       s.Layer,
+		Title:       s.Title,
+		Description: s.Description,
+		Tags:        nonNil(s.Tags),
+		Aliases:     s.Aliases,
+		Meta:        s.Meta,
+	}
+	for _, p := range s.Params {
+		po := ParamOutput{Name: p.Name, Description: p.Description, Values: p.Values, Secret: p.Secret}
+		if p.HasDefault {
+			po.Default = &p.Default
+		}
+		out.Params = append(out.Params, po)
+	}
+	if withBody {
+		out.Body = s.Body
+	}
+	return out
+}
+
+// NewSectionListOutput converts a list of sections without bodies.
+func NewSectionListOutput(sections []manual.Section) []SectionOutput {
+	out := make([]SectionOutput, len(sections))
+	for i, s := range sections {
+		out[i] = NewSectionOutput(s, false)
+	}
+	return out
+}
+
+// NewSearchOutput converts search results.
+func NewSearchOutput(results []fs.SearchResult) []SearchResultOutput {
+	out := make([]SearchResultOutput, len(results))
+	for i, r := range results {
+		out[i] = SearchResultOutput{
+			Layer:    r.Layer,
+			File:     r.File,
+			Title:    r.Title,
+			Score:    r.Score,
+			Line:     r.Line,
+			Content:  r.Content,
+			Headings: nonNil(r.Headings),
+			Before:   r.Before,
+			After:    r.After,
+		}
+	}
+	return out
+}
+
+// NewTagOutput converts tag counts.
+func NewTagOutput(tags []manual.TagCount) []TagOutput {
+	out := make([]TagOutput, len(tags))
+	for i, t := range tags {
+		out[i] = TagOutput{Tag: t.Tag, Count: t.Count}
+	}
+	return out
+}
+
+// NewSessionOutput converts a session summary, with its events if given.
+func NewSessionOutput(s fs.Session, events []fs.SessionEvent) SessionOutput {
+	return SessionOutput{
+		ID:      s.ID,
+		Section: s.Section,
+		Heading: s.Heading,
+		User:    s.User,
+		Started: s.Started,
+		Status:  s.Status,
+		Ran:     s.Ran,
+		Skipped: s.Skipped,
+		Events:  events,
+	}
+}
+
+// NewChecklistOutput converts a section's task-list items and the progress
+// saved for them.
+func NewChecklistOutput(tasks []manual.Task, c fs.Checklist) ChecklistOutput {
+	out := ChecklistOutput{Section: c.Section, Run: c.Run, Total: len(tasks), Items: make([]ChecklistItemOutput, len(tasks))}
+	if !c.Started.IsZero() {
+		out.Started = &c.Started
+	}
+	for i, t := range tasks {
+		item := ChecklistItemOutput{Text: t.Text, Headings: t.Path, Line: t.Line, Done: t.Checked}
+		if item.Headings == nil {
+			item.Headings = []string{}
+		}
+		if mark, ok := c.Done[t.Key]; ok && !t.Checked {
+			item.Done, item.Time, item.User = true, &mark.Time, mark.User
+		}
+		if item.Done {
+			out.Done++
+		}
+		out.Items[i] = item
+	}
+	return out
+}
+
+// NewHeadingOutput converts a section's headings.
+func NewHeadingOutput(headings []manual.Heading) []HeadingOutput {
+	out := make([]HeadingOutput, len(headings))
+	for i, h := range headings {
+		out[i] = HeadingOutput{Level: h.Level, Text: h.Text, Slug: h.Slug, Line: h.Line}
+	}
+	return out
+}
+
+// NewTemplateOutput converts template presets.
+func NewTemplateOutput(templates []manual.Template) []TemplateOutput {
+	out := make([]TemplateOutput, len(templates))
+	for i, t := range templates {
+		sections := make([]TemplateSectionOutput, len(t.Sections))
+		for j, s := range t.Sections {
+			sections[j] = TemplateSectionOutput{
+				Name:        s.Name,
+				Group:       s.Group,
+				Title:       s.Title,
+				Description: s.Description,
+				Tags:        nonNil(s.Tags),
+			}
+		}
+		out[i] = TemplateOutput{Name: t.Name, Description: t.Description, Sections: sections}
+	}
+	return out
+}
+
+// Render writes v to w as JSON or YAML.
+func Render(w io.Writer, f Format, v any) error {
+	switch f {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	return fmt.Errorf("format %q has no structured renderer", f)
+}
+
+// nonNil returns an empty slice for nil so JSON renders [] rather than null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hojooneum/pm/internal/manual"
+)
+
+func TestParseFormat(t *testing.T) {
+	for _, in := range []string{"", "text", "json", "yaml"} {
+		if _, err := ParseFormat(in); err != nil {
+			t.Errorf("ParseFormat(%q): unexpected error %v", in, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestRender_SectionJSON(t *testing.T) {
+	s := manual.Section{
+		Name:  "deploy",
+		Group: "core",
+		Title: "Deployment Guide",
+		Meta:  map[string]any{"owner": "sre"},
+		Body:  "# Deploy",
+	}
+
+	var buf bytes.Buffer
+	if err := Render(&buf, FormatJSON, NewSectionOutput(s, false)); err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"name", "group", "title", "tags", "meta"} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("expected key %q in %s", key, buf.String())
+		}
+	}
+	if _, ok := got["body"]; ok {
+		t.Error("body should be omitted when not requested")
+	}
+	if tags, ok := got["tags"].([]any); !ok || len(tags) != 0 {
+		t.Errorf("expected empty tags array, got %v", got["tags"])
+	}
+}
+
+func TestRender_YAML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, FormatYAML, NewTagOutput([]manual.TagCount{{Tag: "incident", Count: 2}})); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "- tag: incident\n  count: 2\n") {
+		t.Errorf("unexpected YAML: %q", buf.String())
+	}
+}
+
+func TestNewSectionOutput_Params(t *testing.T) {
+	s := manual.Section{
+		Name: "rollout",
+		Params: []manual.Param{
+			{Name: "namespace", Values: []string{"staging", "prod"}},
+			{Name: "version", Default: "", HasDefault: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Render(&buf, FormatJSON, NewSectionOutput(s, false)); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"name": "namespace"`, `"default": ""`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %s in %s", want, buf.String())
+		}
+	}
+	if strings.Count(buf.String(), `"default"`) != 1 {
+		t.Errorf("expected default only for the optional parameter: %s", buf.String())
+	}
+}
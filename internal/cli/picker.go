@@ -0,0 +1,329 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hojooneum/pm/internal/manual"
+	"golang.org/x/term"
+)
+
+// ErrCancelled is returned by PickSection when the user quits without choosing.
+var ErrCancelled = errors.New("selection cancelled")
+
+// PickSection runs a full-screen fuzzy finder over sections and returns the
+// index of the chosen one. Keystrokes are read from in, which is switched to
+// raw mode for the duration; the finder is drawn on out. Typing filters by
+// name, title, tags and description; the body of the highlighted section is
+// previewed below the list.
+//
+// Keys: Up/Down or Ctrl-P/Ctrl-N move, Enter selects, Esc or Ctrl-C cancels,
+// Backspace deletes a character and Ctrl-U clears the query.
+func PickSection(in, out *os.File, sections []manual.Section) (int, error) {
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return 0, err
+	}
+	defer term.Restore(int(in.Fd()), state)
+
+	fmt.Fprint(out, "\x1b[?1049h") // alternate screen
+	defer fmt.Fprint(out, "\x1b[?1049l")
+
+	p := newPicker(sections)
+	buf := make([]byte, 64)
+	for {
+		width, height, err := term.GetSize(int(out.Fd()))
+		if err != nil || width <= 0 || height <= 0 {
+			width, height = 80, 24
+		}
+		p.render(out, width, height)
+
+		n, err := in.Read(buf)
+		if err != nil {
+			return 0, err
+		}
+		for _, k := range decodeKeys(buf[:n]) {
+			switch p.handle(k) {
+			case pickerSelect:
+				if len(p.matches) == 0 {
+					continue
+				}
+				return p.matches[p.cursor], nil
+			case pickerCancel:
+				return 0, ErrCancelled
+			}
+		}
+	}
+}
+
+// keyKind classifies a decoded keystroke.
+type keyKind int
+
+const (
+	keyRune keyKind = iota
+	keyUp
+	keyDown
+	keyEnter
+	keyBackspace
+	keyClear
+	keyCancel
+)
+
+type key struct {
+	kind keyKind
+	r    rune // for keyRune
+}
+
+// decodeKeys splits raw terminal input into keystrokes. Unknown escape
+// sequences and control characters are dropped.
+func decodeKeys(b []byte) []key {
+	var keys []key
+	for len(b) > 0 {
+		if b[0] == 0x1b {
+			if len(b) >= 3 && (b[1] == '[' || b[1] == 'O') {
+				switch b[2] {
+				case 'A':
+					keys = append(keys, key{kind: keyUp})
+				case 'B':
+					keys = append(keys, key{kind: keyDown})
+				}
+				// Skip the rest of the sequence (parameters end at a letter or '~').
+				i := 2
+				for i < len(b) && !(b[i] >= 'A' && b[i] <= 'Z' || b[i] >= 'a' && b[i] <= 'z' || b[i] == '~') {
+					i++
+				}
+				b = b[min(i+1, len(b)):]
+				continue
+			}
+			keys = append(keys, key{kind: keyCancel})
+			b = b[1:]
+			continue
+		}
+
+		r, size := utf8.DecodeRune(b)
+		b = b[size:]
+		switch r {
+		case '\r', '\n':
+			keys = append(keys, key{kind: keyEnter})
+		case 0x7f, 0x08:
+			keys = append(keys, key{kind: keyBackspace})
+		case 0x15: // Ctrl-U
+			keys = append(keys, key{kind: keyClear})
+		case 0x03, 0x04: // Ctrl-C, Ctrl-D
+			keys = append(keys, key{kind: keyCancel})
+		case 0x10: // Ctrl-P
+			keys = append(keys, key{kind: keyUp})
+		case 0x0e: // Ctrl-N
+			keys = append(keys, key{kind: keyDown})
+		default:
+			if unicode.IsPrint(r) {
+				keys = append(keys, key{kind: keyRune, r: r})
+			}
+		}
+	}
+	return keys
+}
+
+type pickerAction int
+
+const (
+	pickerContinue pickerAction = iota
+	pickerSelect
+	pickerCancel
+)
+
+// picker holds the state of the fuzzy finder between keystrokes.
+type picker struct {
+	sections []manual.Section
+	haystack []string // lowercase text matched against the query, per section
+	query    []rune
+	matches  []int // indexes into sections, best match first
+	cursor   int   // index into matches
+	offset   int   // first visible row of matches
+}
+
+func newPicker(sections []manual.Section) *picker {
+	p := &picker{sections: sections, haystack: make([]string, len(sections))}
+	for i, s := range sections {
+		fields := []string{s.Group + "/" + s.Name, s.Title, strings.Join(s.Tags, " "), strings.Join(s.Aliases, " "), s.Description}
+		p.haystack[i] = strings.ToLower(strings.Join(fields, " "))
+	}
+	p.filter()
+	return p
+}
+
+// handle applies one keystroke to the picker.
+func (p *picker) handle(k key) pickerAction {
+	switch k.kind {
+	case keyRune:
+		p.query = append(p.query, k.r)
+		p.filter()
+	case keyBackspace:
+		if len(p.query) > 0 {
+			p.query = p.query[:len(p.query)-1]
+			p.filter()
+		}
+	case keyClear:
+		p.query = nil
+		p.filter()
+	case keyUp:
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case keyDown:
+		if p.cursor < len(p.matches)-1 {
+			p.cursor++
+		}
+	case keyEnter:
+		return pickerSelect
+	case keyCancel:
+		return pickerCancel
+	}
+	return pickerContinue
+}
+
+// filter recomputes matches for the current query. Every space-separated word
+// of the query must fuzzy-match; sections are ordered by their summed score.
+func (p *picker) filter() {
+	words := strings.Fields(strings.ToLower(string(p.query)))
+	scores := make(map[int]int)
+	p.matches = p.matches[:0]
+	for i, text := range p.haystack {
+		total, ok := 0, true
+		for _, w := range words {
+			score, matched := fuzzyScore(w, text)
+			if !matched {
+				ok = false
+				break
+			}
+			total += score
+		}
+		if ok {
+			p.matches = append(p.matches, i)
+			scores[i] = total
+		}
+	}
+	sort.SliceStable(p.matches, func(a, b int) bool {
+		return scores[p.matches[a]] > scores[p.matches[b]]
+	})
+	p.cursor, p.offset = 0, 0
+}
+
+// fuzzyScore reports whether the runes of pattern appear in text in order and,
+// if so, how well: consecutive runes and runes at the start of a word score higher,
+// and gaps between matched runes cost a point each.
+func fuzzyScore(pattern, text string) (int, bool) {
+	pr := []rune(pattern)
+	if len(pr) == 0 {
+		return 0, true
+	}
+
+	score, j, last := 0, 0, -1
+	prev := ' '
+	for i, r := range []rune(text) {
+		if j < len(pr) && r == pr[j] {
+			score++
+			switch {
+			case last == i-1:
+				score += 10
+			case !unicode.IsLetter(prev) && !unicode.IsDigit(prev):
+				score += 8
+			}
+			if last >= 0 {
+				score -= i - last - 1
+			}
+			last = i
+			j++
+		}
+		prev = r
+	}
+	return score, j == len(pr)
+}
+
+// render draws the picker: the query line, the match list and a preview of the
+// highlighted section's body, separated by a rule.
+func (p *picker) render(w io.Writer, width, height int) {
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+
+	line := func(s string) {
+		b.WriteString(truncate(s, width))
+		b.WriteString("\x1b[K\r\n")
+	}
+
+	line("> " + string(p.query))
+	line(fmt.Sprintf("  %d/%d", len(p.matches), len(p.sections)))
+
+	rows := max(1, min(len(p.sections), (height-3)/2))
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+rows {
+		p.offset = p.cursor - rows + 1
+	}
+	for r := 0; r < rows; r++ {
+		i := p.offset + r
+		if i >= len(p.matches) {
+			line("")
+			continue
+		}
+		text := pickerLine(p.sections[p.matches[i]])
+		if i == p.cursor {
+			b.WriteString("\x1b[7m")
+			b.WriteString(truncate("> "+text, width))
+			b.WriteString("\x1b[0m\x1b[K\r\n")
+		} else {
+			line("  " + text)
+		}
+	}
+	line(strings.Repeat("─", width))
+
+	var preview []string
+	if len(p.matches) > 0 {
+		preview = strings.Split(p.sections[p.matches[p.cursor]].Body, "\n")
+	}
+	for r := 0; r < height-rows-3; r++ {
+		text := ""
+		if r < len(preview) {
+			text = strings.ReplaceAll(preview[r], "\t", "    ")
+		}
+		if r == height-rows-4 {
+			b.WriteString(truncate(text, width) + "\x1b[K")
+		} else {
+			line(text)
+		}
+	}
+
+	// Leave the cursor at the end of the query.
+	fmt.Fprintf(&b, "\x1b[1;%dH", 3+len(p.query))
+	io.WriteString(w, b.String())
+}
+
+// pickerLine formats one row of the match list.
+func pickerLine(s manual.Section) string {
+	text := fmt.Sprintf("%-24s %s", s.Group+"/"+s.Name, s.Title)
+	if len(s.Tags) > 0 {
+		text += "  [" + strings.Join(s.Tags, ", ") + "]"
+	}
+	if s.Description != "" {
+		text += "  " + s.Description
+	}
+	return text
+}
+
+// truncate shortens s to at most width runes.
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:width])
+}
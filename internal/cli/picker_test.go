@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hojooneum/pm/internal/manual"
+)
+
+func testPickerSections() []manual.Section {
+	return []manual.Section{
+		{Name: "backup", Group: "core", Title: "Backup & Recovery", Tags: []string{"backup"}, Body: "# Backup"},
+		{Name: "deploy", Group: "core", Title: "Deployment Guide", Tags: []string{"release"}, Body: "# Deploy\nsteps"},
+		{Name: "troubleshoot", Group: "core", Title: "Troubleshooting Guide", Description: "When things break", Body: "# Troubleshoot"},
+	}
+}
+
+func TestDecodeKeys(t *testing.T) {
+	keys := decodeKeys([]byte("a\x1b[A\x1b[B\x1b[1;5C\r\x7f\x15\x03\x1bé"))
+	want := []key{
+		{kind: keyRune, r: 'a'},
+		{kind: keyUp},
+		{kind: keyDown},
+		{kind: keyEnter},
+		{kind: keyBackspace},
+		{kind: keyClear},
+		{kind: keyCancel},
+		{kind: keyCancel},
+		{kind: keyRune, r: 'é'},
+	}
+	if len(keys) != len(want) {
+		t.Fatalf("got %d keys %v, want %d", len(keys), keys, len(want))
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("key %d: got %+v, want %+v", i, keys[i], want[i])
+		}
+	}
+}
+
+func TestFuzzyScore(t *testing.T) {
+	if _, ok := fuzzyScore("trbl", "core/troubleshoot"); !ok {
+		t.Error("expected trbl to match troubleshoot")
+	}
+	if _, ok := fuzzyScore("xyz", "core/troubleshoot"); ok {
+		t.Error("expected xyz not to match")
+	}
+
+	contiguous, _ := fuzzyScore("dep", "deploy")
+	scattered, _ := fuzzyScore("dep", "disk error report")
+	if contiguous <= scattered {
+		t.Errorf("expected contiguous match to score higher: %d vs %d", contiguous, scattered)
+	}
+}
+
+func TestPicker_Filter(t *testing.T) {
+	p := newPicker(testPickerSections())
+	if len(p.matches) != 3 {
+		t.Fatalf("expected all sections with an empty query, got %v", p.matches)
+	}
+
+	for _, r := range "guide" {
+		p.handle(key{kind: keyRune, r: r})
+	}
+	if len(p.matches) != 2 {
+		t.Errorf("expected 2 matches for 'guide', got %v", p.matches)
+	}
+
+	p.handle(key{kind: keyClear})
+	for _, r := range "things" {
+		p.handle(key{kind: keyRune, r: r})
+	}
+	if len(p.matches) != 1 || p.matches[0] != 2 {
+		t.Errorf("expected description match on troubleshoot, got %v", p.matches)
+	}
+
+	p.handle(key{kind: keyClear})
+	for _, r := range "release deploy" {
+		p.handle(key{kind: keyRune, r: r})
+	}
+	if len(p.matches) != 1 || p.matches[0] != 1 {
+		t.Errorf("expected every word to match deploy, got %v", p.matches)
+	}
+}
+
+func TestPicker_Navigation(t *testing.T) {
+	p := newPicker(testPickerSections())
+
+	p.handle(key{kind: keyUp})
+	if p.cursor != 0 {
+		t.Errorf("cursor should stay at the top, got %d", p.cursor)
+	}
+	p.handle(key{kind: keyDown})
+	p.handle(key{kind: keyDown})
+	p.handle(key{kind: keyDown})
+	if p.cursor != 2 {
+		t.Errorf("cursor should stop at the last match, got %d", p.cursor)
+	}
+
+	if a := p.handle(key{kind: keyEnter}); a != pickerSelect {
+		t.Errorf("expected select, got %v", a)
+	}
+	if a := p.handle(key{kind: keyCancel}); a != pickerCancel {
+		t.Errorf("expected cancel, got %v", a)
+	}
+}
+
+func TestPicker_Render(t *testing.T) {
+	p := newPicker(testPickerSections())
+	p.handle(key{kind: keyDown})
+
+	var buf bytes.Buffer
+	p.render(&buf, 60, 12)
+	out := buf.String()
+
+	for _, want := range []string{"> \x1b[K", "3/3", "core/deploy", "Deployment Guide", "steps"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected render to contain %q:\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\r\n") {
+		plain := strings.NewReplacer("\x1b[K", "", "\x1b[7m", "", "\x1b[0m", "", "\x1b[H", "").Replace(line)
+		if n := len([]rune(plain)); n > 60 && !strings.Contains(plain, "\x1b[") {
+			t.Errorf("line wider than 60 columns (%d): %q", n, plain)
+		}
+	}
+}
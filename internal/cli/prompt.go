@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
@@ -78,3 +79,72 @@ func SelectOption(scanner *bufio.Scanner, w io.Writer, prompt string, options []
 
 	return defaultIdx, nil
 }
+
+// PromptValue prints a prompt and reads a non-empty line. EOF returns io.EOF
+// and maxRetries empty inputs return an error.
+func PromptValue(scanner *bufio.Scanner, w io.Writer, prompt string) (string, error) {
+	for range maxRetries {
+		fmt.Fprintf(w, "%s: ", prompt)
+
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		}
+		if input := strings.TrimSpace(scanner.Text()); input != "" {
+			return input, nil
+		}
+		fmt.Fprintln(w, "  Please enter a value.")
+	}
+
+	return "", errors.New("no value entered")
+}
+
+// Choice is one answer to a Choose prompt.
+type Choice struct {
+	Key   string // what the user types, e.g. "s"
+	Label string // what the choice does, e.g. "skip"; typing it also works
+}
+
+// Choose prints a prompt listing the choices' keys, e.g. "[Y/s/e/a]", and
+// reads a choice, returning its index. defaultIdx is used when the user
+// presses Enter. Unlike ConfirmYesNo, Choose never guesses: EOF returns
+// io.EOF and maxRetries invalid inputs return an error, since the choices
+// may start actions.
+func Choose(scanner *bufio.Scanner, w io.Writer, prompt string, choices []Choice, defaultIdx int) (int, error) {
+	keys := make([]string, len(choices))
+	described := make([]string, len(choices))
+	for i, c := range choices {
+		keys[i] = c.Key
+		if i == defaultIdx {
+			keys[i] = strings.ToUpper(c.Key)
+		}
+		described[i] = fmt.Sprintf("%s (%s)", c.Key, c.Label)
+	}
+	help := strings.Join(described[:len(described)-1], ", ") + " or " + described[len(described)-1]
+
+	for range maxRetries {
+		fmt.Fprintf(w, "%s [%s] ", prompt, strings.Join(keys, "/"))
+
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return 0, err
+			}
+			return 0, io.EOF
+		}
+		input := strings.ToLower(strings.TrimSpace(scanner.Text()))
+
+		if input == "" {
+			return defaultIdx, nil
+		}
+		for i, c := range choices {
+			if input == strings.ToLower(c.Key) || input == strings.ToLower(c.Label) {
+				return i, nil
+			}
+		}
+		fmt.Fprintf(w, "  Please enter %s.\n", help)
+	}
+
+	return 0, errors.New("no valid choice entered")
+}
@@ -77,3 +77,68 @@ func TestSelectOption(t *testing.T) {
 		})
 	}
 }
+
+func TestChoose(t *testing.T) {
+	choices := []Choice{{"y", "run"}, {"s", "skip"}, {"a", "abort"}}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"key", "s\n", 1, false},
+		{"upper key", "A\n", 2, false},
+		{"label", "skip\n", 1, false},
+		{"empty uses default", "\n", 0, false},
+		{"invalid then key", "x\na\n", 2, false},
+		{"EOF", "", 0, true},
+		{"three invalids", "x\nz\nq\n", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tt.input))
+			var out bytes.Buffer
+			got, err := Choose(scanner, &out, "Run?", choices, 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+			if !strings.HasPrefix(out.String(), "Run? [Y/s/a] ") {
+				t.Errorf("unexpected prompt %q", out.String())
+			}
+		})
+	}
+}
+
+func TestPromptValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"value", "staging\n", "staging", false},
+		{"trimmed", "  prod \n", "prod", false},
+		{"empty then value", "\nprod\n", "prod", false},
+		{"EOF", "", "", true},
+		{"three empties", "\n\n\n", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tt.input))
+			var out bytes.Buffer
+			got, err := PromptValue(scanner, &out, "namespace")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
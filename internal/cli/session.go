@@ -0,0 +1,224 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hojooneum/pm/internal/fs"
+)
+
+// PrintSessionList writes recorded sessions to w, most recent first.
+func PrintSessionList(w io.Writer, sessions []fs.Session) {
+	if len(sessions) == 0 {
+		fmt.Fprintln(w, "No sessions recorded yet.")
+		return
+	}
+
+	fmt.Fprintln(w, "Sessions:")
+	for i := len(sessions) - 1; i >= 0; i-- {
+		s := sessions[i]
+		section := s.Section
+		if s.Heading != "" {
+			section += "#" + s.Heading
+		}
+		fmt.Fprintf(w, "  %-34s %-24s %-10s %s\n", s.ID, section, sessionStatus(s.Status), sessionSteps(s))
+	}
+}
+
+// PrintSession writes a session's details and each step's command, result
+// and recorded output to w.
+func PrintSession(w io.Writer, s fs.Session, events []fs.SessionEvent) {
+	fmt.Fprintf(w, "Session %s\n", s.ID)
+	for _, e := range events {
+		switch e.Type {
+		case fs.EventStart:
+			fmt.Fprintf(w, "  Section:  %s\n", sessionSection(e))
+			fmt.Fprintf(w, "  Started:  %s%s\n", e.Time.UTC().Format("2006-01-02 15:04:05 MST"), sessionWho(e))
+			if len(e.Params) > 0 {
+				fmt.Fprintf(w, "  Params:   %s\n", sessionParams(e.Params))
+			}
+			fmt.Fprintf(w, "  Status:   %s (%s)\n", sessionStatus(s.Status), sessionSteps(s))
+		case fs.EventStep:
+			fmt.Fprintln(w)
+			fmt.Fprintf(w, "[%s] %s: %s\n", clock(e.Time), stepTitle(e), stepResult(e))
+			for _, line := range strings.Split(strings.TrimRight(e.Command, "\n"), "\n") {
+				fmt.Fprintln(w, "    $ "+line)
+			}
+			if out := strings.TrimRight(e.Output, "\n"); out != "" {
+				if e.Truncated {
+					fmt.Fprintln(w, "    | ...")
+				}
+				for _, line := range strings.Split(out, "\n") {
+					fmt.Fprintln(w, "    | "+line)
+				}
+			}
+		case fs.EventEnd:
+			fmt.Fprintln(w)
+			fmt.Fprintf(w, "[%s] Session %s\n", clock(e.Time), e.Status)
+		}
+	}
+}
+
+// ExportSessionMarkdown writes a session as a markdown timeline for a
+// postmortem: a summary, one timeline entry per event, then each step's
+// command and output.
+func ExportSessionMarkdown(w io.Writer, s fs.Session, events []fs.SessionEvent) {
+	fmt.Fprintf(w, "# Runbook session: %s\n\n", s.Section)
+	fmt.Fprintf(w, "- **Session:** `%s`\n", s.ID)
+	for _, e := range events {
+		if e.Type != fs.EventStart {
+			continue
+		}
+		fmt.Fprintf(w, "- **Section:** `%s`\n", sessionSection(e))
+		fmt.Fprintf(w, "- **Started:** %s%s\n", e.Time.UTC().Format("2006-01-02 15:04:05 MST"), sessionWho(e))
+		if len(e.Params) > 0 {
+			fmt.Fprintf(w, "- **Parameters:** `%s`\n", sessionParams(e.Params))
+		}
+	}
+	fmt.Fprintf(w, "- **Status:** %s (%s)\n", sessionStatus(s.Status), sessionSteps(s))
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Timeline")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Time (UTC) | Event | Result |")
+	fmt.Fprintln(w, "|------------|-------|--------|")
+	for _, e := range events {
+		switch e.Type {
+		case fs.EventStart:
+			fmt.Fprintf(w, "| %s | Session started | |\n", clock(e.Time))
+		case fs.EventStep:
+			fmt.Fprintf(w, "| %s | %s | %s |\n", clock(e.Time), tableCell(stepTitle(e)), tableCell(stepResult(e)))
+		case fs.EventEnd:
+			fmt.Fprintf(w, "| %s | Session %s | |\n", clock(e.Time), e.Status)
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Steps")
+	for _, e := range events {
+		if e.Type != fs.EventStep {
+			continue
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "### %s\n\n", stepTitle(e))
+		fmt.Fprintf(w, "Started at %s UTC; %s.\n\n", clock(e.Time), stepResult(e))
+		fence := codeFence(e.Command + e.Output)
+		fmt.Fprintf(w, "%sbash\n%s\n%s\n", fence, strings.TrimRight(e.Command, "\n"), fence)
+		if out := strings.TrimRight(e.Output, "\n"); out != "" {
+			fmt.Fprintln(w)
+			if e.Truncated {
+				fmt.Fprintf(w, "Output (last %d bytes):\n\n", len(e.Output))
+			} else {
+				fmt.Fprint(w, "Output:\n\n")
+			}
+			fmt.Fprintf(w, "%s\n%s\n%s\n", fence, out, fence)
+		}
+	}
+}
+
+// sessionSection returns the section a start event ran, with its heading.
+func sessionSection(e fs.SessionEvent) string {
+	if e.Heading != "" {
+		return e.Section + "#" + e.Heading
+	}
+	return e.Section
+}
+
+// sessionWho returns " by user on host" for a start event.
+func sessionWho(e fs.SessionEvent) string {
+	var who string
+	if e.User != "" {
+		who += " by " + e.User
+	}
+	if e.Host != "" {
+		who += " on " + e.Host
+	}
+	return who
+}
+
+// sessionParams formats recorded parameters as sorted name=value pairs.
+func sessionParams(params map[string]string) string {
+	pairs := make([]string, 0, len(params))
+	for k, v := range params {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+// sessionStatus describes a session's status; an empty one means the run
+// never recorded its end.
+func sessionStatus(status string) string {
+	if status == "" {
+		return "incomplete"
+	}
+	return status
+}
+
+// sessionSteps summarizes the steps run and skipped.
+func sessionSteps(s fs.Session) string {
+	text := fmt.Sprintf("%d step(s) run", s.Ran)
+	if s.Skipped > 0 {
+		text += fmt.Sprintf(", %d skipped", s.Skipped)
+	}
+	return text
+}
+
+// stepTitle returns "Step n/total" followed by the step's headings.
+func stepTitle(e fs.SessionEvent) string {
+	title := fmt.Sprintf("Step %d/%d", e.Step, e.Total)
+	if len(e.Headings) > 0 {
+		title += ": " + strings.Join(e.Headings, " > ")
+	}
+	return title
+}
+
+// stepResult describes what happened to a step.
+func stepResult(e fs.SessionEvent) string {
+	if e.Action == fs.ActionSkip {
+		return "skipped"
+	}
+	var result string
+	switch {
+	case e.ExitCode == nil:
+		result = "ran"
+	case *e.ExitCode == 0:
+		result = "ran, exit code 0"
+	default:
+		result = fmt.Sprintf("failed, exit code %d", *e.ExitCode)
+	}
+	if e.End != nil {
+		result += ", " + e.End.Sub(e.Time).Round(100*time.Millisecond).String()
+	}
+	if e.Edited {
+		result += " (edited)"
+	}
+	return result
+}
+
+// clock formats a time of day in UTC.
+func clock(t time.Time) string {
+	return t.UTC().Format("15:04:05")
+}
+
+// tableCell escapes pipes in a markdown table cell.
+func tableCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// codeFence returns a backtick fence longer than any run of backticks in s.
+func codeFence(s string) string {
+	longest, run := 0, 0
+	for _, c := range s {
+		if c == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hojooneum/pm/internal/fs"
+)
+
+func testSession() (fs.Session, []fs.SessionEvent) {
+	start := time.Date(2026, 10, 18, 14, 25, 1, 0, time.UTC)
+	end := start.Add(3 * time.Second)
+	failed := 2
+	events := []fs.SessionEvent{
+		{Type: fs.EventStart, Time: start, Section: "ops/deploy", User: "ana", Host: "box", Params: map[string]string{"token": "********", "env": "prod"}},
+		{Type: fs.EventStep, Time: start.Add(time.Second), Step: 1, Total: 2, Headings: []string{"Deploy", "Apply"}, Command: "kubectl apply -f a|b.yaml\n", Action: fs.ActionRun, End: &end, ExitCode: &failed, Output: "error: ```boom```\n"},
+		{Type: fs.EventStep, Time: end, Step: 2, Total: 2, Command: "make verify\n", Action: fs.ActionSkip},
+		{Type: fs.EventEnd, Time: end, Status: fs.StatusFailed},
+	}
+	return fs.SummarizeSession("20261018T142501Z-deploy", events), events
+}
+
+func TestExportSessionMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	s, events := testSession()
+	ExportSessionMarkdown(&buf, s, events)
+	out := buf.String()
+
+	for _, want := range []string{
+		"# Runbook session: ops/deploy\n",
+		"- **Started:** 2026-10-18 14:25:01 UTC by ana on box\n",
+		"- **Parameters:** `env=prod, token=********`\n",
+		"- **Status:** failed (1 step(s) run, 1 skipped)\n",
+		"| 14:25:02 | Step 1/2: Deploy > Apply | failed, exit code 2, 2s |\n",
+		"| 14:25:04 | Step 2/2 | skipped |\n",
+		"````bash\nkubectl apply -f a|b.yaml\n````\n",
+		"````\nerror: ```boom```\n````\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrintSession(t *testing.T) {
+	var buf bytes.Buffer
+	s, events := testSession()
+	PrintSession(&buf, s, events)
+	out := buf.String()
+
+	for _, want := range []string{
+		"Session 20261018T142501Z-deploy\n",
+		"  Status:   failed (1 step(s) run, 1 skipped)\n",
+		"[14:25:02] Step 1/2: Deploy > Apply: failed, exit code 2, 2s\n    $ kubectl apply -f a|b.yaml\n    | error: ```boom```\n",
+		"[14:25:04] Step 2/2: skipped\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
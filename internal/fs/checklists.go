@@ -0,0 +1,218 @@
+package fs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ChecklistsDir is the hidden directory inside a manual where pm check saves
+// progress through checklists, one JSON file per run of a section's
+// checklist: <group>/<name>/<run>.json.
+const ChecklistsDir = ".checklists"
+
+// Checklist is the progress of one run through a section's task list, kept
+// outside the section so its markdown is never changed.
+type Checklist struct {
+	Section string                   `json:"section"` // group/name
+	Run     string                   `json:"run"`     // see ChecklistRun
+	Started time.Time                `json:"started"` // when the first item was ticked
+	Done    map[string]ChecklistMark `json:"done"`    // by manual.Task.Key
+}
+
+// ChecklistMark records who ticked an item and when.
+type ChecklistMark struct {
+	Time time.Time `json:"time"`
+	User string    `json:"user,omitempty"`
+}
+
+// NewChecklist returns a run of a section's checklist with nothing done yet.
+func NewChecklist(section, run string) Checklist {
+	return Checklist{Section: section, Run: run, Done: make(map[string]ChecklistMark)}
+}
+
+// ChecklistRun returns the default name of a run started at t: its UTC time,
+// e.g. 20261018T093000Z, so runs sort by when they started.
+func ChecklistRun(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+var checklistRunPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidateChecklistRun returns an error if run cannot name a checklist run.
+func ValidateChecklistRun(run string) error {
+	if !checklistRunPattern.MatchString(run) {
+		return fmt.Errorf("invalid run name %q: use letters, digits, '.', '_' and '-'", run)
+	}
+	return nil
+}
+
+// checklistDir returns the directory holding the runs of a group/name section.
+func checklistDir(l Layer, section string) string {
+	return filepath.Join(l.Dir(), ChecklistsDir, filepath.FromSlash(section))
+}
+
+// checklistPath returns the state file of a run of a group/name section.
+func checklistPath(l Layer, section, run string) string {
+	return filepath.Join(checklistDir(l, section), run+".json")
+}
+
+// ReadChecklist returns the saved progress of a run through a section's
+// checklist, or an empty checklist if there is none.
+func ReadChecklist(l Layer, section, run string) (Checklist, error) {
+	return readChecklist(checklistPath(l, section, run), NewChecklist(section, run))
+}
+
+func readChecklist(path string, c Checklist) (Checklist, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("%s: %w", path, err)
+	}
+	if c.Done == nil {
+		c.Done = make(map[string]ChecklistMark)
+	}
+	return c, nil
+}
+
+// LatestChecklist returns the most recently started run through a section's
+// checklist. It reports false if no run has been saved.
+func LatestChecklist(l Layer, section string) (Checklist, bool, error) {
+	dir := checklistDir(l, section)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return Checklist{}, false, nil
+	}
+	if err != nil {
+		return Checklist{}, false, err
+	}
+
+	var latest Checklist
+	found := false
+	for _, e := range entries {
+		run, ok := strings.CutSuffix(e.Name(), ".json")
+		if e.IsDir() || !ok {
+			continue
+		}
+		c, err := readChecklist(filepath.Join(dir, e.Name()), NewChecklist(section, run))
+		if err != nil {
+			return Checklist{}, false, err
+		}
+		if !found || c.Started.After(latest.Started) || c.Started.Equal(latest.Started) && c.Run > latest.Run {
+			latest, found = c, true
+		}
+	}
+	return latest, found, nil
+}
+
+// checklistLockWait is how long WriteChecklist waits for another pm check
+// saving the same run.
+const checklistLockWait = 5 * time.Second
+
+// WriteChecklist saves progress through a checklist, merged with what another
+// pm check working on the same run has saved meanwhile: items ticked there
+// are kept, with the earlier mark winning for items ticked in both. It returns
+// the merged checklist. Saving takes a lock file next to the run's, and the
+// file is replaced in one step, so an interrupted write never loses earlier
+// progress. Saved progress is kept out of version control.
+func WriteChecklist(l Layer, c Checklist) (Checklist, error) {
+	path := checklistPath(l, c.Section, c.Run)
+	if err := ensureIgnoredDir(filepath.Join(l.Dir(), ChecklistsDir)); err != nil {
+		return c, err
+	}
+	if err := EnsureDir(filepath.Dir(path)); err != nil {
+		return c, err
+	}
+	unlock, err := lockFile(path+".lock", checklistLockWait)
+	if err != nil {
+		return c, err
+	}
+	defer unlock()
+
+	saved, err := readChecklist(path, NewChecklist(c.Section, c.Run))
+	if err != nil {
+		return c, err
+	}
+	c = mergeChecklists(saved, c)
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return c, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return c, err
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return c, err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return c, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return c, err
+	}
+	return c, nil
+}
+
+// mergeChecklists returns the progress of saved and c together.
+func mergeChecklists(saved, c Checklist) Checklist {
+	merged := NewChecklist(c.Section, c.Run)
+	merged.Started = c.Started
+	if !saved.Started.IsZero() && (merged.Started.IsZero() || saved.Started.Before(merged.Started)) {
+		merged.Started = saved.Started
+	}
+	for key, mark := range c.Done {
+		merged.Done[key] = mark
+	}
+	for key, mark := range saved.Done {
+		if other, ok := merged.Done[key]; !ok || mark.Time.Before(other.Time) {
+			merged.Done[key] = mark
+		}
+	}
+	return merged
+}
+
+// lockFile creates the lock file at path, waiting up to wait for another
+// process holding it, and returns a function releasing it.
+func lockFile(path string, wait time.Duration) (func(), error) {
+	deadline := time.Now().Add(wait)
+	for {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is locked by another pm check; remove %s if none is running", filepath.Base(strings.TrimSuffix(path, ".lock")), path)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// ResetChecklist deletes the saved progress of a run through a section's
+// checklist. It reports whether there was any.
+func ResetChecklist(l Layer, section, run string) (bool, error) {
+	err := os.Remove(checklistPath(l, section, run))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
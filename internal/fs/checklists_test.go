@@ -0,0 +1,139 @@
+package fs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestChecklist(t *testing.T) {
+	l := Layer{Name: RootLayer, Root: setupTestPM(t)}
+
+	if _, ok, err := LatestChecklist(l, "core/deploy"); err != nil || ok {
+		t.Fatalf("expected no runs, got %v, %v", ok, err)
+	}
+	c, err := ReadChecklist(l, "core/deploy", "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Section != "core/deploy" || c.Run != "v1" || len(c.Done) != 0 || !c.Started.IsZero() {
+		t.Fatalf("expected an empty checklist, got %+v", c)
+	}
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	c.Started = now
+	c.Done["Deploy > Checklist > All tests passing"] = ChecklistMark{Time: now, User: "ana"}
+	if _, err := WriteChecklist(l, c); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(l.Dir(), ChecklistsDir, ".gitignore")); err != nil || string(data) != "*\n" {
+		t.Errorf("expected the checklists directory to be ignored, got %q, %v", data, err)
+	}
+
+	got, err := ReadChecklist(l, "core/deploy", "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Started.Equal(now) || got.Done["Deploy > Checklist > All tests passing"].User != "ana" {
+		t.Errorf("checklist did not round-trip: %+v", got)
+	}
+	if other, _ := ReadChecklist(l, "custom/deploy", "v1"); len(other.Done) != 0 {
+		t.Errorf("expected sections to have separate checklists, got %+v", other)
+	}
+	if other, _ := ReadChecklist(l, "core/deploy", "v2"); len(other.Done) != 0 {
+		t.Errorf("expected runs to have separate checklists, got %+v", other)
+	}
+
+	next := NewChecklist("core/deploy", ChecklistRun(now.Add(time.Hour)))
+	next.Started = now.Add(time.Hour)
+	next.Done["Deploy > Checklist > All tests passing"] = ChecklistMark{Time: next.Started}
+	if _, err := WriteChecklist(l, next); err != nil {
+		t.Fatal(err)
+	}
+	latest, ok, err := LatestChecklist(l, "core/deploy")
+	if err != nil || !ok || latest.Run != "20261018T100000Z" {
+		t.Errorf("expected the later run, got %+v, %v, %v", latest, ok, err)
+	}
+
+	if ok, err := ResetChecklist(l, "core/deploy", latest.Run); err != nil || !ok {
+		t.Fatalf("reset: got %v, %v", ok, err)
+	}
+	if ok, err := ResetChecklist(l, "core/deploy", latest.Run); err != nil || ok {
+		t.Errorf("second reset: got %v, %v", ok, err)
+	}
+	if latest, _, _ := LatestChecklist(l, "core/deploy"); latest.Run != "v1" {
+		t.Errorf("expected the remaining run after reset, got %+v", latest)
+	}
+}
+
+func TestValidateChecklistRun(t *testing.T) {
+	for _, run := range []string{"v1.42", "2026-10-18", "release_3"} {
+		if err := ValidateChecklistRun(run); err != nil {
+			t.Errorf("%q: %v", run, err)
+		}
+	}
+	for _, run := range []string{"", "../x", "a/b", ".hidden", "with space"} {
+		if err := ValidateChecklistRun(run); err == nil {
+			t.Errorf("%q: expected error", run)
+		}
+	}
+}
+
+func TestWriteChecklist_Merge(t *testing.T) {
+	l := Layer{Name: RootLayer, Root: setupTestPM(t)}
+	start := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	// Two pm check runs of the same run, each starting from an empty checklist.
+	a := NewChecklist("core/deploy", "v1")
+	a.Started = start.Add(time.Minute)
+	a.Done["Tests pass"] = ChecklistMark{Time: a.Started, User: "ana"}
+	b := NewChecklist("core/deploy", "v1")
+	b.Started = start
+	b.Done["Plan written"] = ChecklistMark{Time: start, User: "bo"}
+	b.Done["Tests pass"] = ChecklistMark{Time: start.Add(2 * time.Minute), User: "bo"}
+
+	if _, err := WriteChecklist(l, a); err != nil {
+		t.Fatal(err)
+	}
+	merged, err := WriteChecklist(l, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := ReadChecklist(l, "core/deploy", "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []Checklist{merged, saved} {
+		if len(c.Done) != 2 || c.Done["Tests pass"].User != "ana" || !c.Started.Equal(start) {
+			t.Errorf("expected both runs' ticks, the earlier winning, got %+v", c)
+		}
+	}
+
+	lock := checklistPath(l, "core/deploy", "v1") + ".lock"
+	if _, err := os.Stat(lock); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the lock to be released, got %v", err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(lock))
+	if len(entries) != 1 {
+		t.Errorf("expected only the run's file, got %v", entries)
+	}
+}
+
+func TestLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.json.lock")
+	unlock, err := lockFile(path, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lockFile(path, 100*time.Millisecond); err == nil {
+		t.Error("expected a held lock to time out")
+	}
+	unlock()
+	unlock, err = lockFile(path, 0)
+	if err != nil {
+		t.Fatalf("expected the released lock to be free, got %v", err)
+	}
+	unlock()
+}
@@ -0,0 +1,70 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// ConfigFile is the name of the settings file in a layer's manual directory,
+// e.g. .pm/config.yaml or ~/.config/pm/config.yaml.
+const ConfigFile = "config.yaml"
+
+// Config holds user settings. Like git, a project's settings take precedence
+// over the user's global ones.
+type Config struct {
+	Pager         string // command used to page long output, e.g. "less -R"
+	PagerDisabled bool   // "pager: false" turns paging off
+}
+
+// LoadConfig reads config.yaml from each layer, nearest first; a setting in a
+// nearer layer overrides the same setting further up. Missing files are skipped.
+//
+//	pager: less -R     # or "pager: false" to never page
+func LoadConfig(layers []Layer) (Config, error) {
+	var cfg Config
+	pagerSet := false
+	for _, l := range layers {
+		path := filepath.Join(l.Dir(), ConfigFile)
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Config{}, err
+		}
+
+		var doc struct {
+			Pager yaml.Node `yaml:"pager"`
+		}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return Config{}, fmt.Errorf("%s: %w", path, err)
+		}
+
+		if doc.Pager.Kind != 0 && !pagerSet {
+			pagerSet = true
+			if err := decodePager(&doc.Pager, &cfg); err != nil {
+				return Config{}, fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// decodePager accepts a pager command or a boolean; true keeps the default pager.
+func decodePager(n *yaml.Node, cfg *Config) error {
+	if n.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: pager must be a command or false", n.Line)
+	}
+	if n.Tag == "!!bool" {
+		on, _ := strconv.ParseBool(n.Value)
+		cfg.PagerDisabled = !on
+		return nil
+	}
+	cfg.Pager = n.Value
+	return nil
+}
@@ -0,0 +1,55 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := setupTestPM(t)
+	global := t.TempDir()
+	project := Layer{Name: RootLayer, Root: dir}
+	globalLayer := Layer{Name: GlobalGroup, Root: global, Global: true}
+
+	t.Run("no files", func(t *testing.T) {
+		cfg, err := LoadConfig([]Layer{project, globalLayer})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg != (Config{}) {
+			t.Errorf("expected zero config, got %+v", cfg)
+		}
+	})
+
+	t.Run("global only", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(global, ConfigFile), []byte("pager: most\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := LoadConfig([]Layer{project, globalLayer})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Pager != "most" || cfg.PagerDisabled {
+			t.Errorf("expected global pager, got %+v", cfg)
+		}
+	})
+
+	t.Run("project overrides global", func(t *testing.T) {
+		writeTestFile(t, dir, ConfigFile, "pager: false\n")
+		cfg, err := LoadConfig([]Layer{project, globalLayer})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cfg.PagerDisabled || cfg.Pager != "" {
+			t.Errorf("expected project to disable the pager, got %+v", cfg)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		writeTestFile(t, dir, ConfigFile, "pager: [less]\n")
+		if _, err := LoadConfig([]Layer{project}); err == nil {
+			t.Error("expected error for a list-valued pager")
+		}
+	})
+}
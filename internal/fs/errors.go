@@ -0,0 +1,63 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoManual is returned when no .pm/ directory can be found.
+var ErrNoManual = errors.New("no .pm/ directory found in the current directory or its parents")
+
+// SectionNotFoundError is returned when no section matches a name.
+type SectionNotFoundError struct {
+	Name string
+}
+
+func (e *SectionNotFoundError) Error() string {
+	return fmt.Sprintf("section %q not found", e.Name)
+}
+
+// AmbiguousSectionError is returned when a name matches more than one section
+// and none of them takes precedence.
+type AmbiguousSectionError struct {
+	Name       string
+	Candidates []Entry
+}
+
+func (e *AmbiguousSectionError) Error() string {
+	names := make([]string, len(e.Candidates))
+	for i, c := range e.Candidates {
+		names[i] = c.Group + "/" + c.Name
+	}
+	return fmt.Sprintf("section %q is ambiguous (candidates: %s)", e.Name, strings.Join(names, ", "))
+}
+
+// SectionExistsError is returned when creating a section whose name is already
+// taken by a section in the same layer.
+type SectionExistsError struct {
+	Existing Entry
+}
+
+func (e *SectionExistsError) Error() string {
+	return fmt.Sprintf("section %q already exists (%s)", e.Existing.Name, e.Existing.RelPath())
+}
+
+// GroupNotFoundError is returned when a layer has no group with a name.
+type GroupNotFoundError struct {
+	Name string
+}
+
+func (e *GroupNotFoundError) Error() string {
+	return fmt.Sprintf("group %q not found", e.Name)
+}
+
+// GroupExistsError is returned when creating or renaming to a group that
+// already exists in the layer.
+type GroupExistsError struct {
+	Name string
+}
+
+func (e *GroupExistsError) Error() string {
+	return fmt.Sprintf("group %q already exists", e.Name)
+}
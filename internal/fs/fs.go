@@ -1,22 +1,17 @@
 package fs
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+
+	"github.com/hojooneum/pm/internal/manual"
 )
 
 const PMDir = ".pm"
 
-// SearchResult represents a single match from a keyword search.
-type SearchResult struct {
-	File    string // relative path within .pm/, e.g. "core/deploy.md"
-	Line    int    // 1-based line number
-	Content string // matched line content (trimmed)
-}
-
 // DetectPMDir checks whether a .pm/ directory exists under the given root.
 func DetectPMDir(root string) bool {
 	info, err := os.Stat(filepath.Join(root, PMDir))
@@ -34,7 +29,12 @@ func PMPath(root string) string {
 // ListMarkdownFiles returns .md filenames (without extension) under .pm/<group>/.
 // group should be "core" or "custom".
 func ListMarkdownFiles(root, group string) ([]string, error) {
-	dir := filepath.Join(root, PMDir, group)
+	return listMarkdown(filepath.Join(root, PMDir, group))
+}
+
+// listMarkdown returns .md filenames (without extension) directly inside dir.
+// A missing directory yields no names and no error.
+func listMarkdown(dir string) ([]string, error) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -64,70 +64,145 @@ func ReadFile(root, relPath string) (string, error) {
 	return string(data), nil
 }
 
-// FindSection looks for <name>.md in core/ first, then custom/.
-// Returns the group ("core" or "custom") and the relative path within .pm/.
+// FindSection resolves a section name across all layers. An exact filename
+// match wins, nearest layer first, so the global manual is only consulted when
+// no project layer has the section. Otherwise the merged manual is searched for,
+// in order of preference, a frontmatter alias or title equal to name, a filename
+// or alias starting with name, and a filename, alias or title containing the
+// letters of name in order (so "trbl" finds "troubleshoot").
+// name may be qualified as "group/name" to restrict the search to one group.
 // Comparison is case-insensitive.
-func FindSection(root, name string) (group, relPath string, err error) {
-	lower := strings.ToLower(name)
-
-	groups, err := ListGroups(root)
-	if err != nil {
-		return "", "", err
+//
+// A *SectionNotFoundError is returned when nothing matches, and an
+// *AmbiguousSectionError when the best match is not unique.
+func FindSection(layers []Layer, name string) (Entry, error) {
+	group, base := "", name
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		group, base = name[:i], name[i+1:]
 	}
+	lower := strings.ToLower(base)
 
-	for _, g := range groups {
-		files, err := ListMarkdownFiles(root, g)
+	for _, l := range layers {
+		groups, err := l.Groups()
 		if err != nil {
-			return "", "", err
+			return Entry{}, err
 		}
-		for _, f := range files {
-			if strings.ToLower(f) == lower {
-				return g, filepath.Join(g, f+".md"), nil
+
+		var matches []Entry
+		for _, g := range groups {
+			if group != "" && !strings.EqualFold(g, group) {
+				continue
+			}
+			files, err := l.Files(g)
+			if err != nil {
+				return Entry{}, err
+			}
+			for _, f := range files {
+				if strings.ToLower(f) == lower {
+					matches = append(matches, Entry{Layer: l, Group: g, Name: f})
+				}
 			}
 		}
+
+		switch len(matches) {
+		case 0:
+			continue
+		case 1:
+			return matches[0], nil
+		default:
+			return Entry{}, &AmbiguousSectionError{Name: name, Candidates: matches}
+		}
 	}
-	return "", "", fmt.Errorf("section %q not found", name)
+	return fuzzyFindSection(layers, group, lower, name)
 }
 
-// Search scans all .md files under .pm/ for lines containing keyword (case-insensitive).
-func Search(root, keyword string) ([]SearchResult, error) {
-	pmRoot := filepath.Join(root, PMDir)
-	lowerKW := strings.ToLower(keyword)
+// Match tiers used by fuzzyFindSection, best first.
+const (
+	matchExact  = iota + 1 // alias or title equals the query
+	matchPrefix            // filename or alias starts with the query
+	matchFuzzy             // query is a subsequence of the filename, an alias or the title
+)
 
-	var results []SearchResult
-	err := filepath.Walk(pmRoot, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+// fuzzyFindSection is FindSection's fallback when no filename matches exactly.
+// Only the candidates of the best tier are considered.
+func fuzzyFindSection(layers []Layer, group, query, name string) (Entry, error) {
+	entries, err := ListEntries(layers)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	best := 0
+	var matches []Entry
+	for _, e := range entries {
+		if group != "" && !strings.EqualFold(e.Group, group) {
+			continue
 		}
-		if info.IsDir() || !strings.HasSuffix(info.Name(), ".md") {
-			return nil
+		tier := matchTier(e, query)
+		switch {
+		case tier == 0 || (best != 0 && tier > best):
+			continue
+		case tier < best || best == 0:
+			best, matches = tier, nil
 		}
+		matches = append(matches, e)
+	}
 
-		rel, _ := filepath.Rel(pmRoot, path)
+	switch len(matches) {
+	case 0:
+		return Entry{}, &SectionNotFoundError{Name: name}
+	case 1:
+		return matches[0], nil
+	default:
+		return Entry{}, &AmbiguousSectionError{Name: name, Candidates: matches}
+	}
+}
 
-		f, err := os.Open(path)
-		if err != nil {
-			return err
+// matchTier reports how well an entry matches a lowercase query, or 0 for no match.
+// Entries whose frontmatter cannot be parsed are matched on their filename only.
+func matchTier(e Entry, query string) int {
+	var title string
+	var aliases []string
+	if raw, err := e.Read(); err == nil {
+		if s, err := manual.ParseSection(e.Name, e.Group, raw); err == nil {
+			title, aliases = strings.ToLower(s.Title), s.Aliases
 		}
-		defer f.Close()
-
-		scanner := bufio.NewScanner(f)
-		lineNum := 0
-		for scanner.Scan() {
-			lineNum++
-			line := scanner.Text()
-			if strings.Contains(strings.ToLower(line), lowerKW) {
-				results = append(results, SearchResult{
-					File:    rel,
-					Line:    lineNum,
-					Content: strings.TrimSpace(line),
-				})
-			}
+	}
+	for i, a := range aliases {
+		aliases[i] = strings.ToLower(a)
+	}
+	name := strings.ToLower(e.Name)
+
+	if title == query || slices.Contains(aliases, query) {
+		return matchExact
+	}
+	if strings.HasPrefix(name, query) || slices.ContainsFunc(aliases, func(a string) bool {
+		return strings.HasPrefix(a, query)
+	}) {
+		return matchPrefix
+	}
+	for _, s := range append([]string{name, title}, aliases...) {
+		if isSubsequence(query, s) {
+			return matchFuzzy
 		}
-		return scanner.Err()
-	})
+	}
+	return 0
+}
 
-	return results, err
+// isSubsequence reports whether the runes of sub appear in s in order.
+func isSubsequence(sub, s string) bool {
+	if sub == "" {
+		return false
+	}
+	rs := []rune(sub)
+	for _, r := range s {
+		if r == rs[0] {
+			rs = rs[1:]
+			if len(rs) == 0 {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // WriteFileIfNotExists creates a file only if it doesn't already exist.
@@ -149,3 +224,13 @@ func WriteFileIfNotExists(path, content string) (created bool, err error) {
 func EnsureDir(path string) error {
 	return os.MkdirAll(path, 0o755)
 }
+
+// ensureIgnoredDir creates a directory like EnsureDir, with a .gitignore so
+// that what pm keeps there never ends up in version control.
+func ensureIgnoredDir(path string) error {
+	if err := EnsureDir(path); err != nil {
+		return err
+	}
+	_, err := WriteFileIfNotExists(filepath.Join(path, ".gitignore"), "*\n")
+	return err
+}
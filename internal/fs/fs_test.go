@@ -1,6 +1,7 @@
 package fs
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -8,6 +9,8 @@ import (
 
 func setupTestPM(t *testing.T) string {
 	t.Helper()
+	// Keep the developer's real global manual out of the tests.
+	t.Setenv(GlobalEnv, filepath.Join(t.TempDir(), "no-global"))
 	dir := t.TempDir()
 	for _, sub := range []string{"core", "custom"} {
 		if err := os.MkdirAll(filepath.Join(dir, PMDir, sub), 0o755); err != nil {
@@ -103,67 +106,119 @@ func TestFindSection(t *testing.T) {
 	dir := setupTestPM(t)
 	writeTestFile(t, dir, "core/deploy.md", "# Deploy")
 	writeTestFile(t, dir, "custom/myapp.md", "# MyApp")
+	layers := []Layer{{Name: RootLayer, Root: dir}}
 
 	t.Run("finds core", func(t *testing.T) {
-		group, relPath, err := FindSection(dir, "deploy")
+		e, err := FindSection(layers, "deploy")
 		if err != nil {
 			t.Fatal(err)
 		}
-		if group != "core" || relPath != "core/deploy.md" {
-			t.Errorf("unexpected: group=%s path=%s", group, relPath)
+		if e.Group != "core" || e.RelPath() != "core/deploy.md" {
+			t.Errorf("unexpected: group=%s path=%s", e.Group, e.RelPath())
 		}
 	})
 
 	t.Run("finds custom", func(t *testing.T) {
-		group, relPath, err := FindSection(dir, "myapp")
+		e, err := FindSection(layers, "myapp")
 		if err != nil {
 			t.Fatal(err)
 		}
-		if group != "custom" || relPath != "custom/myapp.md" {
-			t.Errorf("unexpected: group=%s path=%s", group, relPath)
+		if e.Group != "custom" || e.RelPath() != "custom/myapp.md" {
+			t.Errorf("unexpected: group=%s path=%s", e.Group, e.RelPath())
 		}
 	})
 
 	t.Run("case insensitive", func(t *testing.T) {
-		_, _, err := FindSection(dir, "DEPLOY")
+		_, err := FindSection(layers, "DEPLOY")
 		if err != nil {
 			t.Error("expected case-insensitive match")
 		}
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		_, _, err := FindSection(dir, "nonexistent")
-		if err == nil {
-			t.Error("expected error for missing section")
+		_, err := FindSection(layers, "nonexistent")
+		var nf *SectionNotFoundError
+		if !errors.As(err, &nf) {
+			t.Errorf("expected *SectionNotFoundError, got %v", err)
 		}
 	})
 }
 
-func TestSearch(t *testing.T) {
+func TestFindSection_Ambiguous(t *testing.T) {
 	dir := setupTestPM(t)
-	writeTestFile(t, dir, "core/deploy.md", "line 1\nTODO: fix this\nline 3")
-	writeTestFile(t, dir, "core/backup.md", "no match here")
-	writeTestFile(t, dir, "custom/app.md", "another TODO item")
+	writeTestFile(t, dir, "core/deploy.md", "# Core deploy")
+	writeTestFile(t, dir, "custom/deploy.md", "# Custom deploy")
+	layers := []Layer{{Name: RootLayer, Root: dir}}
+
+	_, err := FindSection(layers, "deploy")
+	var amb *AmbiguousSectionError
+	if !errors.As(err, &amb) {
+		t.Fatalf("expected *AmbiguousSectionError, got %v", err)
+	}
+	if len(amb.Candidates) != 2 {
+		t.Errorf("expected 2 candidates, got %d", len(amb.Candidates))
+	}
 
-	results, err := Search(dir, "TODO")
+	e, err := FindSection(layers, "custom/deploy")
 	if err != nil {
 		t.Fatal(err)
 	}
-
-	if len(results) != 2 {
-		t.Fatalf("expected 2 results, got %d", len(results))
+	if e.Group != "custom" {
+		t.Errorf("expected qualified name to pick custom, got %q", e.Group)
 	}
+}
 
-	// Verify first result
-	if results[0].Line != 2 {
-		t.Errorf("expected line 2, got %d", results[0].Line)
+func TestFindSection_Fuzzy(t *testing.T) {
+	dir := setupTestPM(t)
+	writeTestFile(t, dir, "core/troubleshoot.md", "---\ntitle: Troubleshooting Guide\naliases: [debug]\n---\n# T")
+	writeTestFile(t, dir, "core/deploy.md", "---\ntitle: Deployment\n---\n# D")
+	writeTestFile(t, dir, "core/deps.md", "# Dependencies")
+	writeTestFile(t, dir, "custom/debugging.md", "# Debugging notes")
+	layers := []Layer{{Name: RootLayer, Root: dir}}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"trbl", "troubleshoot"},
+		{"trouble", "troubleshoot"},
+		{"Troubleshooting Guide", "troubleshoot"},
+		{"debug", "troubleshoot"}, // exact alias beats the "debugging" prefix
+		{"deploy", "deploy"},
+		{"deployment", "deploy"},
+		{"debugg", "debugging"},
+		{"custom/dbg", "debugging"},
 	}
-
-	// Verify case insensitive
-	results2, _ := Search(dir, "todo")
-	if len(results2) != 2 {
-		t.Errorf("expected case-insensitive match, got %d results", len(results2))
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			e, err := FindSection(layers, tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.Name != tt.want {
+				t.Errorf("FindSection(%q) = %s, want %s", tt.query, e.Name, tt.want)
+			}
+		})
 	}
+
+	t.Run("ambiguous prefix", func(t *testing.T) {
+		_, err := FindSection(layers, "dep")
+		var amb *AmbiguousSectionError
+		if !errors.As(err, &amb) {
+			t.Fatalf("expected *AmbiguousSectionError, got %v", err)
+		}
+		if len(amb.Candidates) != 2 {
+			t.Errorf("expected deploy and deps, got %v", amb.Candidates)
+		}
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		_, err := FindSection(layers, "zzz")
+		var nf *SectionNotFoundError
+		if !errors.As(err, &nf) {
+			t.Errorf("expected *SectionNotFoundError, got %v", err)
+		}
+	})
 }
 
 func TestWriteFileIfNotExists(t *testing.T) {
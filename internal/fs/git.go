@@ -0,0 +1,39 @@
+package fs
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// inGitWorkTree reports whether dir is inside a git work tree and git is available.
+func inGitWorkTree(dir string) bool {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--is-inside-work-tree").Output()
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
+
+// moveFile renames a file, through "git mv" when it is tracked in a git work
+// tree so history follows it. Untracked files, or trees without git, are
+// renamed directly.
+func moveFile(from, to string) error {
+	dir := filepath.Dir(from)
+	if inGitWorkTree(dir) {
+		if err := exec.Command("git", "-C", dir, "mv", from, to).Run(); err == nil {
+			return nil
+		}
+	}
+	return os.Rename(from, to)
+}
+
+// removeFile deletes a file, through "git rm" when it is tracked in a git work
+// tree so the deletion is staged like a move would be.
+func removeFile(path string) error {
+	dir := filepath.Dir(path)
+	if inGitWorkTree(dir) {
+		if err := exec.Command("git", "-C", dir, "rm", "--quiet", path).Run(); err == nil {
+			return nil
+		}
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,43 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// GlobalEnv names the environment variable that overrides the global manual directory.
+const GlobalEnv = "PM_GLOBAL_DIR"
+
+// GlobalGroup is the group (and layer) name under which global sections are listed.
+const GlobalGroup = "global"
+
+// GlobalDir returns the user-global manual directory shared by all projects:
+// $PM_GLOBAL_DIR if set, otherwise $XDG_CONFIG_HOME/pm, falling back to ~/.config/pm.
+// Returns "" when no home directory can be determined.
+func GlobalDir() string {
+	if dir := os.Getenv(GlobalEnv); dir != "" {
+		return dir
+	}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "pm")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "pm")
+}
+
+// GlobalLayer returns the layer for the global manual, if its directory exists.
+// Markdown files directly inside the directory form the single "global" group.
+func GlobalLayer() (Layer, bool) {
+	dir := GlobalDir()
+	if dir == "" {
+		return Layer{}, false
+	}
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return Layer{}, false
+	}
+	return Layer{Name: GlobalGroup, Root: dir, Global: true}, true
+}
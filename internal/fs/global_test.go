@@ -0,0 +1,70 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGlobalDir(t *testing.T) {
+	t.Run("env override", func(t *testing.T) {
+		t.Setenv(GlobalEnv, "/tmp/pm-global")
+		if got := GlobalDir(); got != "/tmp/pm-global" {
+			t.Errorf("expected env override, got %q", got)
+		}
+	})
+
+	t.Run("xdg", func(t *testing.T) {
+		t.Setenv(GlobalEnv, "")
+		t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+		if got := GlobalDir(); got != filepath.Join("/tmp/xdg", "pm") {
+			t.Errorf("expected XDG config dir, got %q", got)
+		}
+	})
+}
+
+func TestGlobalLayer_Fallback(t *testing.T) {
+	dir := setupTestPM(t)
+	global := t.TempDir()
+	t.Setenv(GlobalEnv, global)
+
+	writeTestFile(t, dir, "core/deploy.md", "# Project deploy")
+	for name, content := range map[string]string{"vpn.md": "# VPN", "deploy.md": "# Global deploy"} {
+		if err := os.WriteFile(filepath.Join(global, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	layers, err := DiscoverLayers(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layers) != 2 || !layers[1].Global {
+		t.Fatalf("expected project layer followed by global, got %+v", layers)
+	}
+
+	e, err := FindSection(layers, "vpn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Group != GlobalGroup || e.Path() != filepath.Join(global, "vpn.md") {
+		t.Errorf("unexpected global entry: group=%s path=%s", e.Group, e.Path())
+	}
+
+	e, err = FindSection(layers, "deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Layer.Global {
+		t.Error("expected project section to take precedence over global")
+	}
+
+	entries, err := ListEntries(layers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := entries[len(entries)-1]
+	if len(entries) != 2 || last.Name != "vpn" || last.Group != GlobalGroup {
+		t.Errorf("expected global group listed last, got %+v", entries)
+	}
+}
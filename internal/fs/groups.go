@@ -1,16 +1,121 @@
 package fs
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
 )
 
-// ListGroups returns subdirectory names under .pm/, sorted with "core" first,
-// others alphabetically, and "custom" last.
+// GroupFile is the optional metadata file inside a group directory, e.g.
+// .pm/core/_group.yaml.
+const GroupFile = "_group.yaml"
+
+// GroupMeta is the metadata a group may declare in its _group.yaml:
+//
+//	title: Platform runbooks   # replaces "Core sections" in listings
+//	description: What to do when the pager goes off
+//	weight: -10                # lower sorts first; groups default to 0
+//	icon: 🚒
+type GroupMeta struct {
+	Title       string `yaml:"title,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	Weight      int    `yaml:"weight,omitempty"`
+	Icon        string `yaml:"icon,omitempty"`
+}
+
+// IsZero reports whether no metadata is set.
+func (m GroupMeta) IsZero() bool {
+	return m == GroupMeta{}
+}
+
+// ReadGroupMeta reads the _group.yaml in a group directory. A missing file
+// yields zero metadata and no error.
+func ReadGroupMeta(dir string) (GroupMeta, error) {
+	path := filepath.Join(dir, GroupFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return GroupMeta{}, nil
+	}
+	if err != nil {
+		return GroupMeta{}, err
+	}
+	var meta GroupMeta
+	if err := yaml.Unmarshal(data, &meta); err != nil {
+		return GroupMeta{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return meta, nil
+}
+
+// GroupMetas returns the metadata of every group in the layers. When several
+// layers have the same group, the nearest _group.yaml wins. Group metadata is
+// cosmetic, so a _group.yaml that cannot be read or parsed counts as empty;
+// the problems are returned for the caller to report as warnings.
+func GroupMetas(layers []Layer) (map[string]GroupMeta, []error) {
+	metas := make(map[string]GroupMeta)
+	var problems []error
+	for i := len(layers) - 1; i >= 0; i-- {
+		l := layers[i]
+		if l.Global {
+			continue
+		}
+		groups, err := groupDirs(l.Root)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		layerMetas, errs := readGroupMetas(l, groups)
+		problems = append(problems, errs...)
+		for g, meta := range layerMetas {
+			if _, ok := metas[g]; !ok || !meta.IsZero() {
+				metas[g] = meta
+			}
+		}
+	}
+	return metas, problems
+}
+
+// readGroupMetas reads the _group.yaml of the given groups of a layer,
+// counting files with problems as empty.
+func readGroupMetas(l Layer, groups []string) (map[string]GroupMeta, []error) {
+	metas := make(map[string]GroupMeta, len(groups))
+	var problems []error
+	for _, g := range groups {
+		meta, err := ReadGroupMeta(l.groupDir(g))
+		if err != nil {
+			problems = append(problems, err)
+		}
+		metas[g] = meta
+	}
+	return metas, problems
+}
+
+// ListGroups returns subdirectory names under .pm/, ordered by the weight in
+// their _group.yaml and then with "core" first, others alphabetically, and
+// "custom" last. Hidden directories such as .pm/.cache are not groups and are
+// skipped. A _group.yaml with problems counts as empty (see GroupMetas).
 func ListGroups(root string) ([]string, error) {
-	pmPath := filepath.Join(root, PMDir)
-	entries, err := os.ReadDir(pmPath)
+	groups, err := groupDirs(root)
+	if err != nil {
+		return nil, err
+	}
+	metas, _ := readGroupMetas(Layer{Root: root}, groups)
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groupLess(metas, groups[i], groups[j])
+	})
+
+	return groups, nil
+}
+
+// groupDirs returns the unsorted group directory names under root's .pm/.
+func groupDirs(root string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(root, PMDir))
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
@@ -20,27 +125,194 @@ func ListGroups(root string) ([]string, error) {
 
 	var groups []string
 	for _, e := range entries {
-		if e.IsDir() {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
 			groups = append(groups, e.Name())
 		}
 	}
-
-	sort.SliceStable(groups, func(i, j int) bool {
-		return groupSortKey(groups[i]) < groupSortKey(groups[j])
-	})
-
 	return groups, nil
 }
 
-// groupSortKey returns a sort key that places "core" first, "custom" last,
-// and everything else alphabetically in between.
+// groupLess orders groups by weight, then by groupSortKey.
+func groupLess(metas map[string]GroupMeta, a, b string) bool {
+	if wa, wb := metas[a].Weight, metas[b].Weight; wa != wb {
+		return wa < wb
+	}
+	return groupSortKey(a) < groupSortKey(b)
+}
+
+// groupSortKey returns a sort key that places "core" first, "custom" and then
+// "global" last, and everything else alphabetically in between.
 func groupSortKey(name string) string {
 	switch name {
 	case "core":
 		return "\x00" // sorts first
 	case "custom":
+		return "\xfe" // sorts after named groups
+	case GlobalGroup:
 		return "\xff" // sorts last
 	default:
 		return "\x01" + name // between core and custom
 	}
 }
+
+// WriteGroupMeta writes a group's _group.yaml, or removes it when meta is zero.
+func WriteGroupMeta(l Layer, group string, meta GroupMeta) error {
+	path := filepath.Join(l.groupDir(group), GroupFile)
+	if meta.IsZero() {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	data, err := yaml.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// CreateGroup creates an empty group in the layer, with a _group.yaml if meta
+// is set. It returns a *GroupExistsError if the group already exists.
+func CreateGroup(l Layer, group string, meta GroupMeta) error {
+	if l.Global {
+		return errors.New("the global manual has no groups")
+	}
+	dir := l.groupDir(group)
+	if _, err := os.Stat(dir); err == nil {
+		return &GroupExistsError{Name: group}
+	}
+	if err := EnsureDir(dir); err != nil {
+		return err
+	}
+	return WriteGroupMeta(l, group, meta)
+}
+
+// GroupEntries returns the sections in one group of the layer. It returns a
+// *GroupNotFoundError if the group does not exist.
+func GroupEntries(l Layer, group string) ([]Entry, error) {
+	if l.Global {
+		return nil, errors.New("the global manual has no groups")
+	}
+	if info, err := os.Stat(l.groupDir(group)); err != nil || !info.IsDir() {
+		return nil, &GroupNotFoundError{Name: group}
+	}
+	files, err := l.Files(group)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, len(files))
+	for i, f := range files {
+		entries[i] = Entry{Layer: l, Group: group, Name: f}
+	}
+	return entries, nil
+}
+
+// RemoveGroup deletes a group with its sections and _group.yaml, turning links
+// to the sections into plain text as RemoveSection does. A group holding
+// anything else, such as notes, images or directories, is left untouched with
+// an error, so nothing is deleted unless all of it can be. It returns the
+// sections whose links were removed.
+func RemoveGroup(l Layer, group string) ([]Entry, error) {
+	entries, err := GroupEntries(l, group)
+	if err != nil {
+		return nil, err
+	}
+	dir := l.groupDir(group)
+	others, err := otherFiles(dir, entries)
+	if err != nil {
+		return nil, err
+	}
+	if len(others) > 0 {
+		return nil, fmt.Errorf("%s holds more than sections and %s (%s); move or delete those first", dir, GroupFile, strings.Join(others, ", "))
+	}
+
+	var updated []Entry
+	for _, e := range entries {
+		changed, err := RemoveSection(e)
+		if err != nil {
+			return updated, err
+		}
+		updated = appendEntries(updated, changed...)
+	}
+	// Sections of the group may have been updated before they were removed.
+	updated = slices.DeleteFunc(updated, func(e Entry) bool { return e.Group == group })
+
+	if err := removeFile(filepath.Join(dir, GroupFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return updated, err
+	}
+	if err := os.Remove(dir); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return updated, fmt.Errorf("removing %s: %w", dir, err)
+	}
+	return updated, nil
+}
+
+// otherFiles returns the names of the files and directories in a group's
+// directory that are neither its sections nor its _group.yaml.
+func otherFiles(dir string, sections []Entry) ([]string, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var others []string
+	for _, f := range files {
+		name := f.Name()
+		if name == GroupFile {
+			continue
+		}
+		if !f.IsDir() && slices.ContainsFunc(sections, func(e Entry) bool { return e.Name+".md" == name }) {
+			continue
+		}
+		others = append(others, name)
+	}
+	return others, nil
+}
+
+// RenameGroup moves every section of a group, and its _group.yaml, into a new
+// group, updating links as MoveSection does. It returns a *GroupExistsError if
+// the new group already exists, and the sections whose links were updated.
+func RenameGroup(l Layer, group, newGroup string) ([]Entry, error) {
+	entries, err := GroupEntries(l, group)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(l.groupDir(newGroup)); err == nil {
+		return nil, &GroupExistsError{Name: newGroup}
+	}
+	if err := EnsureDir(l.groupDir(newGroup)); err != nil {
+		return nil, err
+	}
+
+	var updated []Entry
+	for _, e := range entries {
+		_, changed, err := MoveSection(e, newGroup, e.Name)
+		if err != nil {
+			return updated, err
+		}
+		updated = appendEntries(updated, changed...)
+	}
+	// Links between sections of the group are rewritten while they move one by
+	// one but end up as they were, as do their links to other groups.
+	updated = slices.DeleteFunc(updated, func(e Entry) bool { return e.Group == group || e.Group == newGroup })
+
+	dir := l.groupDir(group)
+	meta := filepath.Join(dir, GroupFile)
+	if _, err := os.Stat(meta); err == nil {
+		if err := moveFile(meta, filepath.Join(l.groupDir(newGroup), GroupFile)); err != nil {
+			return updated, err
+		}
+	}
+	if err := os.Remove(dir); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return updated, fmt.Errorf("removing %s: %w", dir, err)
+	}
+	return updated, nil
+}
+
+// appendEntries appends the entries not already in list.
+func appendEntries(list []Entry, entries ...Entry) []Entry {
+	for _, e := range entries {
+		if !slices.Contains(list, e) {
+			list = append(list, e)
+		}
+	}
+	return list
+}
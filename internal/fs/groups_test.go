@@ -0,0 +1,183 @@
+package fs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestListGroups_Order(t *testing.T) {
+	dir := setupTestPM(t)
+	for _, g := range []string{"ops", "infra", ".cache"} {
+		if err := os.MkdirAll(filepath.Join(dir, PMDir, g), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	groups, err := ListGroups(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"core", "infra", "ops", "custom"}; !slices.Equal(groups, want) {
+		t.Errorf("got %v, want %v", groups, want)
+	}
+
+	writeTestFile(t, dir, "ops/"+GroupFile, "title: Operations\nweight: -1\n")
+	writeTestFile(t, dir, "core/"+GroupFile, "weight: 5\n")
+	groups, err = ListGroups(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"ops", "infra", "custom", "core"}; !slices.Equal(groups, want) {
+		t.Errorf("got %v, want %v", groups, want)
+	}
+
+	// An invalid _group.yaml counts as empty instead of failing.
+	writeTestFile(t, dir, "infra/"+GroupFile, "weight: [1]\n")
+	groups, err = ListGroups(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"ops", "infra", "custom", "core"}; !slices.Equal(groups, want) {
+		t.Errorf("got %v, want %v", groups, want)
+	}
+}
+
+func TestGroupMetas_Layers(t *testing.T) {
+	repo, service := setupLayeredPM(t)
+	writeTestFile(t, repo, "core/"+GroupFile, "title: Org runbooks\nicon: \"*\"\n")
+	writeTestFile(t, repo, "custom/"+GroupFile, "title: Org extras\n")
+	writeTestFile(t, service, "core/"+GroupFile, "title: API runbooks\n")
+
+	layers, err := DiscoverLayers(service)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metas, problems := GroupMetas(layers)
+	if len(problems) > 0 {
+		t.Fatal(problems)
+	}
+	if got := metas["core"]; got != (GroupMeta{Title: "API runbooks"}) {
+		t.Errorf("expected the nearest _group.yaml to win, got %+v", got)
+	}
+	if got := metas["custom"].Title; got != "Org extras" {
+		t.Errorf("expected the root layer's title, got %q", got)
+	}
+
+	writeTestFile(t, service, "custom/"+GroupFile, "weight: [1]\n")
+	metas, problems = GroupMetas(layers)
+	if len(problems) != 1 || !strings.Contains(problems[0].Error(), GroupFile) {
+		t.Errorf("expected one problem naming the file, got %v", problems)
+	}
+	if got := metas["custom"].Title; got != "Org extras" {
+		t.Errorf("expected the invalid file to count as empty, got %q", got)
+	}
+	if _, err := ListEntries(layers); err != nil {
+		t.Errorf("expected listing to ignore the invalid file, got %v", err)
+	}
+}
+
+func TestCreateGroup(t *testing.T) {
+	dir := setupTestPM(t)
+	layer := Layer{Name: RootLayer, Root: dir}
+
+	meta := GroupMeta{Title: "Platform", Weight: -10}
+	if err := CreateGroup(layer, "platform", meta); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadGroupMeta(filepath.Join(dir, PMDir, "platform"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != meta {
+		t.Errorf("got %+v, want %+v", got, meta)
+	}
+
+	if err := CreateGroup(layer, "ops", GroupMeta{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, PMDir, "ops", GroupFile)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected no _group.yaml without metadata, got %v", err)
+	}
+
+	var exists *GroupExistsError
+	if err := CreateGroup(layer, "core", GroupMeta{}); !errors.As(err, &exists) {
+		t.Errorf("expected *GroupExistsError, got %v", err)
+	}
+}
+
+func TestRenameGroup(t *testing.T) {
+	dir := setupTestPM(t)
+	writeTestFile(t, dir, "ops/vpn.md", "See [db](db.md).\n")
+	writeTestFile(t, dir, "ops/db.md", "# DB\n")
+	writeTestFile(t, dir, "ops/"+GroupFile, "title: Operations\n")
+	writeTestFile(t, dir, "core/deploy.md", "Connect to the [VPN](../ops/vpn.md).\n")
+	layer := Layer{Name: RootLayer, Root: dir}
+
+	updated, err := RenameGroup(layer, "ops", "infra")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated) != 1 || updated[0].RelPath() != filepath.Join("core", "deploy.md") {
+		t.Errorf("unexpected updated sections %v", updated)
+	}
+	if _, err := os.Stat(filepath.Join(dir, PMDir, "ops")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected old group directory to be gone, got %v", err)
+	}
+	for rel, want := range map[string]string{
+		"core/deploy.md":     "[VPN](../infra/vpn.md)",
+		"infra/vpn.md":       "[db](db.md)",
+		"infra/" + GroupFile: "title: Operations",
+	} {
+		if content, _ := ReadFile(dir, rel); !strings.Contains(content, want) {
+			t.Errorf("%s: expected %q, got:\n%s", rel, want, content)
+		}
+	}
+
+	var exists *GroupExistsError
+	if _, err := RenameGroup(layer, "infra", "core"); !errors.As(err, &exists) {
+		t.Errorf("expected *GroupExistsError, got %v", err)
+	}
+	var notFound *GroupNotFoundError
+	if _, err := RenameGroup(layer, "ops", "misc"); !errors.As(err, &notFound) {
+		t.Errorf("expected *GroupNotFoundError, got %v", err)
+	}
+}
+
+func TestRemoveGroup(t *testing.T) {
+	dir := setupTestPM(t)
+	writeTestFile(t, dir, "ops/vpn.md", "See [db](db.md).\n")
+	writeTestFile(t, dir, "ops/db.md", "# DB\n")
+	writeTestFile(t, dir, "ops/"+GroupFile, "title: Operations\n")
+	writeTestFile(t, dir, "core/deploy.md", "Connect to the [VPN](../ops/vpn.md).\n")
+	layer := Layer{Name: RootLayer, Root: dir}
+
+	// Files other than sections keep the whole group in place.
+	writeTestFile(t, dir, "ops/diagram.png", "png")
+	if _, err := RemoveGroup(layer, "ops"); err == nil || !strings.Contains(err.Error(), "diagram.png") {
+		t.Fatalf("expected an error naming the other file, got %v", err)
+	}
+	if content, _ := ReadFile(dir, "ops/db.md"); content != "# DB\n" {
+		t.Fatalf("expected sections to be kept, got %q", content)
+	}
+	if err := os.Remove(filepath.Join(dir, PMDir, "ops", "diagram.png")); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := RemoveGroup(layer, "ops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated) != 1 || updated[0].Name != "deploy" {
+		t.Errorf("unexpected updated sections %v", updated)
+	}
+	if _, err := os.Stat(filepath.Join(dir, PMDir, "ops")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected group directory to be removed, got %v", err)
+	}
+	if content, _ := ReadFile(dir, "core/deploy.md"); content != "Connect to the VPN.\n" {
+		t.Errorf("unexpected content %q", content)
+	}
+}
@@ -0,0 +1,314 @@
+package fs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/hojooneum/pm/internal/manual"
+)
+
+// CacheDir is the hidden directory inside a manual where derived data such as
+// the search index is kept. It is safe to delete.
+const CacheDir = ".cache"
+
+const (
+	indexFile    = "search-index.json"
+	indexVersion = 1
+
+	// BM25 parameters.
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// fieldWeights make matches in titles, tags and headings count more than body text.
+var fieldWeights = map[string]float64{
+	"title":       3,
+	"tags":        3,
+	"description": 2,
+	"headings":    2,
+	"body":        1,
+}
+
+// searchIndex is the on-disk index of one layer, stored in <manual>/.cache/.
+type searchIndex struct {
+	Version int                  `json:"version"`
+	Docs    map[string]*indexDoc `json:"docs"` // keyed by path relative to the manual
+}
+
+// indexDoc holds the token counts of one section, per field.
+// A document is re-read when its size or mtime changes, and re-tokenized
+// only when its content hash changes too.
+type indexDoc struct {
+	ModTime int64                     `json:"mtime"`
+	Size    int64                     `json:"size"`
+	Hash    string                    `json:"hash"`
+	Fields  map[string]map[string]int `json:"fields"`
+}
+
+// RankedSearch scores the sections of all layers against q with BM25 and
+// returns their matching lines, best section first. Each result carries its
+// section's score. Shadowed sections are not considered.
+// Terms are matched as whole, case-insensitive words; opts.Before and
+// opts.After control context lines, the other options are ignored.
+func RankedSearch(layers []Layer, q Query, opts SearchOptions) ([]SearchResult, error) {
+	entries, err := ListEntries(layers)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make(map[Entry]*indexDoc, len(entries))
+	for _, l := range layers {
+		idx, err := refreshIndex(l)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.Layer == l {
+				docs[e] = idx.Docs[e.RelPath()]
+			}
+		}
+	}
+
+	scores := scoreDocs(entries, docs, q)
+
+	ranked := make([]Entry, 0, len(scores))
+	for e := range scores {
+		ranked = append(ranked, e)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		si, sj := scores[ranked[i]], scores[ranked[j]]
+		if si != sj {
+			return si > sj
+		}
+		return ranked[i].Path() < ranked[j].Path()
+	})
+
+	m, err := NewMatcher(q, SearchOptions{Word: true, Section: true, Before: opts.Before, After: opts.After})
+	if err != nil {
+		return nil, err
+	}
+	m.highlightOnly = true
+
+	var results []SearchResult
+	for _, e := range ranked {
+		hits, err := searchFile(e.Path(), e.RelPath(), m)
+		if err != nil {
+			return nil, err
+		}
+		for i := range hits {
+			hits[i].Score = scores[e]
+			if len(layers) > 1 {
+				hits[i].Layer = e.Layer.Name
+			}
+		}
+		results = append(results, hits...)
+	}
+	return results, nil
+}
+
+// scoreDocs returns the BM25F score of every entry matching q.
+// An entry matches a clause when it contains every token of the clause's
+// positive terms and none of its negated ones; its score is that of the best
+// matching clause.
+func scoreDocs(entries []Entry, docs map[Entry]*indexDoc, q Query) map[Entry]float64 {
+	tf := make(map[Entry]map[string]float64, len(entries))
+	length := make(map[Entry]float64, len(entries))
+	df := make(map[string]int)
+	var total float64
+	for _, e := range entries {
+		d := docs[e]
+		if d == nil {
+			continue
+		}
+		weighted := make(map[string]float64)
+		for field, counts := range d.Fields {
+			w := fieldWeights[field]
+			for tok, n := range counts {
+				weighted[tok] += w * float64(n)
+				length[e] += w * float64(n)
+			}
+		}
+		for tok := range weighted {
+			df[tok]++
+		}
+		tf[e] = weighted
+		total += length[e]
+	}
+	if len(tf) == 0 {
+		return nil
+	}
+	avgLen := total / float64(len(tf))
+	n := float64(len(tf))
+
+	scores := make(map[Entry]float64)
+	for e, freqs := range tf {
+		for _, clause := range q.Clauses {
+			score, ok := 0.0, true
+			for _, t := range clause {
+				for _, tok := range tokenize(t.Text) {
+					f := freqs[tok]
+					if t.Negate {
+						ok = ok && f == 0
+						continue
+					}
+					if f == 0 {
+						ok = false
+						continue
+					}
+					idf := math.Log(1 + (n-float64(df[tok])+0.5)/(float64(df[tok])+0.5))
+					score += idf * f * (bm25K1 + 1) / (f + bm25K1*(1-bm25B+bm25B*length[e]/avgLen))
+				}
+			}
+			if ok && score > scores[e] {
+				scores[e] = score
+			}
+		}
+	}
+	return scores
+}
+
+// refreshIndex loads a layer's index, brings it up to date with the files on
+// disk, and writes it back if anything changed. A missing, corrupt or
+// outdated index is rebuilt; failing to save it is not an error.
+func refreshIndex(l Layer) (*searchIndex, error) {
+	path := filepath.Join(l.Dir(), CacheDir, indexFile)
+
+	idx := &searchIndex{}
+	if data, err := os.ReadFile(path); err == nil {
+		if json.Unmarshal(data, idx) != nil || idx.Version != indexVersion {
+			idx = &searchIndex{}
+		}
+	}
+	if idx.Docs == nil {
+		idx = &searchIndex{Version: indexVersion, Docs: make(map[string]*indexDoc)}
+	}
+
+	groups, err := l.Groups()
+	if err != nil {
+		return nil, err
+	}
+
+	dirty := false
+	seen := make(map[string]bool)
+	for _, g := range groups {
+		names, err := l.Files(g)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			e := Entry{Layer: l, Group: g, Name: name}
+			rel := e.RelPath()
+			seen[rel] = true
+
+			info, err := os.Stat(e.Path())
+			if err != nil {
+				return nil, err
+			}
+			old := idx.Docs[rel]
+			if old != nil && old.ModTime == info.ModTime().UnixNano() && old.Size == info.Size() {
+				continue
+			}
+
+			raw, err := e.Read()
+			if err != nil {
+				return nil, err
+			}
+			sum := sha256.Sum256([]byte(raw))
+			hash := hex.EncodeToString(sum[:])
+
+			doc := old
+			if doc == nil || doc.Hash != hash {
+				doc = &indexDoc{Hash: hash, Fields: indexFields(name, g, raw)}
+			}
+			doc.ModTime = info.ModTime().UnixNano()
+			doc.Size = info.Size()
+			idx.Docs[rel] = doc
+			dirty = true
+		}
+	}
+	for rel := range idx.Docs {
+		if !seen[rel] {
+			delete(idx.Docs, rel)
+			dirty = true
+		}
+	}
+
+	if dirty {
+		_ = saveIndex(path, idx)
+	}
+	return idx, nil
+}
+
+// saveIndex writes the index atomically, creating the cache directory with a
+// .gitignore so it never ends up in version control.
+func saveIndex(path string, idx *searchIndex) error {
+	dir := filepath.Dir(path)
+	if err := ensureIgnoredDir(dir); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, indexFile+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// indexFields tokenizes a section into the weighted fields used for ranking.
+func indexFields(name, group, raw string) map[string]map[string]int {
+	s, err := manual.ParseSection(name, group, raw)
+	if err != nil {
+		s = manual.Section{Name: name, Group: group, Body: raw}
+	}
+
+	var headings []string
+	for _, h := range manual.ParseHeadings(s.Body) {
+		headings = append(headings, h.Text)
+	}
+
+	fields := map[string]string{
+		"title":       s.Title,
+		"description": s.Description,
+		"tags":        strings.Join(s.Tags, " "),
+		"headings":    strings.Join(headings, " "),
+		"body":        s.Body,
+	}
+	out := make(map[string]map[string]int, len(fields))
+	for field, text := range fields {
+		counts := make(map[string]int)
+		for _, tok := range tokenize(text) {
+			counts[tok]++
+		}
+		if len(counts) > 0 {
+			out[field] = counts
+		}
+	}
+	return out
+}
+
+// tokenize splits text into lowercase words of letters and digits.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
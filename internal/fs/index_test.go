@@ -0,0 +1,85 @@
+package fs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRankedSearch(t *testing.T) {
+	dir := setupTestPM(t)
+	writeTestFile(t, dir, "core/deploy.md", "---\ntitle: Deployment Guide\n---\n\nRun the pipeline. Check postgres afterwards.")
+	writeTestFile(t, dir, "core/postgres.md", "---\ntitle: Postgres Operations\ntags: postgres, database\n---\n\n# Failover\n\nPromote the postgres replica.")
+	writeTestFile(t, dir, "custom/notes.md", "Nothing relevant here.")
+	layers := []Layer{{Name: RootLayer, Root: dir}}
+
+	q, _ := ParseQuery([]string{"postgres"})
+	results, err := RankedSearch(layers, q, SearchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) == 0 {
+		t.Fatal("expected results")
+	}
+	if results[0].File != "core/postgres.md" {
+		t.Errorf("expected title/tag match ranked first, got %s", results[0].File)
+	}
+	last := results[len(results)-1]
+	if last.File != "core/deploy.md" || last.Score >= results[0].Score {
+		t.Errorf("expected body-only match ranked last with a lower score, got %+v", last)
+	}
+
+	q, _ = ParseQuery([]string{"postgres", "-replica"})
+	results, _ = RankedSearch(layers, q, SearchOptions{})
+	for _, r := range results {
+		if r.File == "core/postgres.md" {
+			t.Error("expected excluded term to drop the section")
+		}
+	}
+}
+
+func TestRankedSearch_IndexInvalidation(t *testing.T) {
+	dir := setupTestPM(t)
+	writeTestFile(t, dir, "core/deploy.md", "alpha")
+	layers := []Layer{{Name: RootLayer, Root: dir}}
+
+	q, _ := ParseQuery([]string{"alpha"})
+	if results, _ := RankedSearch(layers, q, SearchOptions{}); len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+
+	indexPath := filepath.Join(dir, PMDir, CacheDir, indexFile)
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		t.Fatalf("expected index to be written: %v", err)
+	}
+	var idx searchIndex
+	if err := json.Unmarshal(data, &idx); err != nil || idx.Docs["core/deploy.md"] == nil {
+		t.Fatalf("unexpected index content: %s", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, PMDir, CacheDir, ".gitignore")); err != nil {
+		t.Error("expected cache .gitignore")
+	}
+
+	// Rewrite the file with new content and a different mtime.
+	writeTestFile(t, dir, "core/deploy.md", "beta")
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(filepath.Join(dir, PMDir, "core/deploy.md"), later, later)
+
+	if results, _ := RankedSearch(layers, q, SearchOptions{}); len(results) != 0 {
+		t.Errorf("expected stale index entry to be refreshed, got %d results", len(results))
+	}
+	q, _ = ParseQuery([]string{"beta"})
+	if results, _ := RankedSearch(layers, q, SearchOptions{}); len(results) != 1 {
+		t.Errorf("expected new content to be indexed")
+	}
+
+	groups, _ := ListGroups(dir)
+	for _, g := range groups {
+		if g == CacheDir {
+			t.Error("cache directory must not be listed as a group")
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package fs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RootLayer is the name of the outermost .pm/ in a stack of nested manuals.
+const RootLayer = "root"
+
+// Layer is one .pm/ directory in a stack of nested manuals, e.g. an org-wide
+// manual at the repository root and a per-service manual below it.
+type Layer struct {
+	Name   string // "root" for the outermost manual, otherwise its path relative to it
+	Root   string // directory containing .pm/, or the global manual directory itself
+	Global bool   // the user-global manual (see GlobalLayer)
+}
+
+// Dir returns the layer's manual directory.
+func (l Layer) Dir() string {
+	if l.Global {
+		return l.Root
+	}
+	return PMPath(l.Root)
+}
+
+// Groups returns the layer's group names in ListGroups order.
+// The global layer always has a single "global" group.
+func (l Layer) Groups() ([]string, error) {
+	if l.Global {
+		return []string{GlobalGroup}, nil
+	}
+	return ListGroups(l.Root)
+}
+
+// Files returns the .md filenames (without extension) in one of the layer's groups.
+func (l Layer) Files(group string) ([]string, error) {
+	return listMarkdown(l.groupDir(group))
+}
+
+// groupDir returns the directory holding a group's sections.
+func (l Layer) groupDir(group string) string {
+	if l.Global {
+		return l.Root
+	}
+	return filepath.Join(PMPath(l.Root), group)
+}
+
+// Entry locates a section file within a layer.
+type Entry struct {
+	Layer Layer
+	Group string
+	Name  string // filename without .md extension
+}
+
+// RelPath returns the entry's path relative to its layer's .pm/, e.g. "core/deploy.md".
+func (e Entry) RelPath() string {
+	return filepath.Join(e.Group, e.Name+".md")
+}
+
+// Path returns the absolute path to the entry's markdown file.
+func (e Entry) Path() string {
+	return filepath.Join(e.Layer.groupDir(e.Group), e.Name+".md")
+}
+
+// Read returns the raw content of the entry's markdown file.
+func (e Entry) Read() (string, error) {
+	data, err := os.ReadFile(e.Path())
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// DiscoverLayers collects every .pm/ from start up to the top of the git work
+// tree (or the filesystem root), nearest first, followed by the global manual if present.
+// The outermost project layer is named "root"; the others are named by their path relative to it.
+func DiscoverLayers(start string) ([]Layer, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return nil, err
+	}
+
+	var roots []string
+	for {
+		if DetectPMDir(dir) {
+			roots = append(roots, dir)
+		}
+		if isGitRoot(dir) {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	if len(roots) == 0 {
+		return nil, nil
+	}
+
+	top := roots[len(roots)-1]
+	layers := make([]Layer, len(roots))
+	for i, r := range roots {
+		name := RootLayer
+		if r != top {
+			rel, err := filepath.Rel(top, r)
+			if err != nil {
+				return nil, err
+			}
+			name = filepath.ToSlash(rel)
+		}
+		layers[i] = Layer{Name: name, Root: r}
+	}
+
+	if g, ok := GlobalLayer(); ok {
+		layers = append(layers, g)
+	}
+	return layers, nil
+}
+
+// SelectLayer returns the layer with the given name.
+func SelectLayer(layers []Layer, name string) (Layer, error) {
+	names := make([]string, len(layers))
+	for i, l := range layers {
+		if l.Name == name {
+			return l, nil
+		}
+		names[i] = l.Name
+	}
+	return Layer{}, fmt.Errorf("layer %q not found (available: %s)", name, strings.Join(names, ", "))
+}
+
+// ListEntries lists the sections of all layers merged into one manual.
+// A section in a nearer layer shadows any same-named section in the layers
+// above it; same-named sections in different groups of one layer are all listed.
+// Entries are ordered by group (see ListGroups), then by name.
+func ListEntries(layers []Layer) ([]Entry, error) {
+	metas, _ := GroupMetas(layers)
+
+	shadowed := make(map[string]bool)
+	var entries []Entry
+	for _, l := range layers {
+		groups, err := l.Groups()
+		if err != nil {
+			return nil, err
+		}
+		var names []string
+		for _, g := range groups {
+			files, err := l.Files(g)
+			if err != nil {
+				return nil, err
+			}
+			for _, n := range files {
+				if shadowed[strings.ToLower(n)] {
+					continue
+				}
+				entries = append(entries, Entry{Layer: l, Group: g, Name: n})
+				names = append(names, n)
+			}
+		}
+		for _, n := range names {
+			shadowed[strings.ToLower(n)] = true
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		gi, gj := entries[i].Group, entries[j].Group
+		if gi != gj {
+			return groupLess(metas, gi, gj)
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
+
+// FindInLayer returns the section with the given name in any group of the layer.
+// Comparison is case-insensitive.
+func FindInLayer(l Layer, name string) (Entry, bool, error) {
+	groups, err := l.Groups()
+	if err != nil {
+		return Entry{}, false, err
+	}
+	for _, g := range groups {
+		files, err := l.Files(g)
+		if err != nil {
+			return Entry{}, false, err
+		}
+		for _, f := range files {
+			if strings.EqualFold(f, name) {
+				return Entry{Layer: l, Group: g, Name: f}, true, nil
+			}
+		}
+	}
+	return Entry{}, false, nil
+}
+
+// CreateSection writes a new section into a group of the layer, creating the
+// group directory if needed. Section names are unique across all groups of a
+// layer, so a *SectionExistsError is returned if any group already has one
+// with the same name; existing files are never overwritten.
+func CreateSection(l Layer, group, name, content string) (Entry, error) {
+	if existing, ok, err := FindInLayer(l, name); err != nil {
+		return Entry{}, err
+	} else if ok {
+		return Entry{}, &SectionExistsError{Existing: existing}
+	}
+
+	if l.Global {
+		group = GlobalGroup
+	}
+	e := Entry{Layer: l, Group: group, Name: name}
+	created, err := WriteFileIfNotExists(e.Path(), content)
+	if err != nil {
+		return Entry{}, err
+	}
+	if !created {
+		return Entry{}, &SectionExistsError{Existing: e}
+	}
+	return e, nil
+}
@@ -0,0 +1,154 @@
+package fs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupLayeredPM creates a repo with a top-level .pm/ and a nested services/api/.pm/.
+// It returns the repo root and the nested service directory.
+func setupLayeredPM(t *testing.T) (repo, service string) {
+	t.Helper()
+	repo = setupTestPM(t)
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	service = filepath.Join(repo, "services", "api")
+	for _, sub := range []string{"core", "custom"} {
+		if err := os.MkdirAll(filepath.Join(service, PMDir, sub), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestFile(t, repo, "core/deploy.md", "# Org deploy")
+	writeTestFile(t, repo, "core/oncall.md", "# On-call")
+	writeTestFile(t, service, "core/deploy.md", "# API deploy")
+	writeTestFile(t, service, "custom/api.md", "# API")
+	return repo, service
+}
+
+func TestDiscoverLayers(t *testing.T) {
+	repo, service := setupLayeredPM(t)
+
+	layers, err := DiscoverLayers(service)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layers) != 2 {
+		t.Fatalf("expected 2 layers, got %d: %v", len(layers), layers)
+	}
+	if layers[0].Name != "services/api" || layers[0].Root != service {
+		t.Errorf("unexpected nearest layer: %+v", layers[0])
+	}
+	if layers[1].Name != RootLayer || layers[1].Root != repo {
+		t.Errorf("unexpected root layer: %+v", layers[1])
+	}
+}
+
+func TestDiscoverLayers_None(t *testing.T) {
+	t.Setenv(GlobalEnv, filepath.Join(t.TempDir(), "no-global"))
+	layers, err := DiscoverLayers(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if layers != nil {
+		t.Errorf("expected no layers, got %v", layers)
+	}
+}
+
+func TestListEntries_Shadowing(t *testing.T) {
+	_, service := setupLayeredPM(t)
+	layers, _ := DiscoverLayers(service)
+
+	entries, err := ListEntries(layers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]string)
+	for _, e := range entries {
+		got[e.Name] = e.Layer.Name
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d: %v", len(entries), got)
+	}
+	if got["deploy"] != "services/api" {
+		t.Errorf("expected nearest deploy to shadow root, got layer %q", got["deploy"])
+	}
+	if got["oncall"] != RootLayer {
+		t.Errorf("expected oncall from root layer, got %q", got["oncall"])
+	}
+}
+
+func TestListEntries_SameLayerDuplicates(t *testing.T) {
+	dir := setupTestPM(t)
+	writeTestFile(t, dir, "core/deploy.md", "# Core deploy")
+	writeTestFile(t, dir, "custom/deploy.md", "# Custom deploy")
+
+	entries, err := ListEntries([]Layer{{Name: RootLayer, Root: dir}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Group != "core" || entries[1].Group != "custom" {
+		t.Errorf("expected deploy from both groups, got %v", entries)
+	}
+}
+
+func TestFindSection_Layers(t *testing.T) {
+	_, service := setupLayeredPM(t)
+	layers, _ := DiscoverLayers(service)
+
+	e, err := FindSection(layers, "deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Layer.Name != "services/api" {
+		t.Errorf("expected nearest layer, got %q", e.Layer.Name)
+	}
+
+	root, err := SelectLayer(layers, RootLayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err = FindSection([]Layer{root}, "deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ := e.Read()
+	if content != "# Org deploy" {
+		t.Errorf("expected root layer deploy, got %q", content)
+	}
+
+	if _, err := SelectLayer(layers, "nope"); err == nil {
+		t.Error("expected error for unknown layer")
+	}
+}
+
+func TestCreateSection(t *testing.T) {
+	dir := setupTestPM(t)
+	writeTestFile(t, dir, "core/deploy.md", "# Deploy")
+	layer := Layer{Name: RootLayer, Root: dir}
+
+	e, err := CreateSection(layer, "ops", "failover", "# Failover")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.RelPath() != filepath.Join("ops", "failover.md") {
+		t.Errorf("unexpected path %s", e.RelPath())
+	}
+	if content, _ := e.Read(); content != "# Failover" {
+		t.Errorf("unexpected content %q", content)
+	}
+
+	for _, tt := range []struct{ group, name string }{{"custom", "deploy"}, {"core", "Deploy"}, {"ops", "failover"}} {
+		_, err := CreateSection(layer, tt.group, tt.name, "overwritten")
+		var exists *SectionExistsError
+		if !errors.As(err, &exists) {
+			t.Errorf("%s/%s: expected *SectionExistsError, got %v", tt.group, tt.name, err)
+		}
+	}
+	if content, _ := ReadFile(dir, "core/deploy.md"); content != "# Deploy" {
+		t.Errorf("existing section was modified: %q", content)
+	}
+}
@@ -0,0 +1,194 @@
+package fs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// inlineLinkRe matches inline links and images: [text](target "title").
+	inlineLinkRe = regexp.MustCompile(`(!?)\[([^\]]*)\]\(\s*([^)\s]+)((?:\s+"[^"]*")?\s*)\)`)
+	// refLinkRe matches reference definitions: [id]: target.
+	refLinkRe = regexp.MustCompile(`(?m)^(\s{0,3}\[[^\]]+\]:[ \t]*)(\S+)`)
+)
+
+// linkRewrite decides what to do with a link to target, a cleaned absolute
+// path. It returns the new absolute path, or unlink to drop the link and keep
+// its text.
+type linkRewrite func(target string) (newTarget string, unlink, changed bool)
+
+// rewriteLinks applies fn to every relative link to a .md file in content,
+// resolving link paths against dir (the directory holding the file the links
+// were written for) and writing them relative to newDir.
+func rewriteLinks(content, dir, newDir string, fn linkRewrite) (string, bool) {
+	changed := false
+	rewrite := func(target string) (string, bool, bool) {
+		path, fragment, _ := strings.Cut(target, "#")
+		if !strings.HasSuffix(path, ".md") || strings.Contains(path, ":") || strings.HasPrefix(path, "/") {
+			return target, false, false
+		}
+		abs := filepath.Clean(filepath.Join(dir, filepath.FromSlash(path)))
+		next, unlink, ok := fn(abs)
+		if unlink {
+			changed = true
+			return "", true, true
+		}
+		if !ok && dir == newDir {
+			return target, false, false
+		}
+		rel, err := filepath.Rel(newDir, next)
+		if err != nil {
+			return target, false, false
+		}
+		out := filepath.ToSlash(rel)
+		if fragment != "" {
+			out += "#" + fragment
+		}
+		if out != target {
+			changed = true
+		}
+		return out, false, out != target
+	}
+
+	content = inlineLinkRe.ReplaceAllStringFunc(content, func(m string) string {
+		sub := inlineLinkRe.FindStringSubmatch(m)
+		image, text, target, title := sub[1], sub[2], sub[3], sub[4]
+		out, unlink, ok := rewrite(target)
+		switch {
+		case unlink && image == "":
+			return text
+		case unlink:
+			return m // dropping an image would lose more than the link
+		case !ok:
+			return m
+		}
+		return fmt.Sprintf("%s[%s](%s%s)", image, text, out, title)
+	})
+	content = refLinkRe.ReplaceAllStringFunc(content, func(m string) string {
+		sub := refLinkRe.FindStringSubmatch(m)
+		if out, unlink, ok := rewrite(sub[2]); ok && !unlink {
+			return sub[1] + out
+		}
+		return m
+	})
+	return content, changed
+}
+
+// updateLinks rewrites the links in every section of the layer with fn and
+// returns the sections that changed. moved, if set, is a section that was just
+// moved from oldPath: its own relative links are re-based to its new location.
+func updateLinks(l Layer, fn linkRewrite, moved *Entry, oldPath string) ([]Entry, error) {
+	entries, err := ListEntries([]Layer{l})
+	if err != nil {
+		return nil, err
+	}
+
+	var updated []Entry
+	for _, e := range entries {
+		content, err := e.Read()
+		if err != nil {
+			return updated, err
+		}
+		dir := filepath.Dir(e.Path())
+		from := dir
+		if moved != nil && e == *moved {
+			from = filepath.Dir(oldPath)
+		}
+		out, changed := rewriteLinks(content, from, dir, fn)
+		if !changed {
+			continue
+		}
+		if err := os.WriteFile(e.Path(), []byte(out), 0o644); err != nil {
+			return updated, err
+		}
+		updated = append(updated, e)
+	}
+	return updated, nil
+}
+
+// LinksTo returns the sections in e's layer that link to e.
+func LinksTo(e Entry) ([]Entry, error) {
+	entries, err := ListEntries([]Layer{e.Layer})
+	if err != nil {
+		return nil, err
+	}
+
+	var linking []Entry
+	for _, other := range entries {
+		if other == e {
+			continue
+		}
+		content, err := other.Read()
+		if err != nil {
+			return nil, err
+		}
+		dir := filepath.Dir(other.Path())
+		found := false
+		rewriteLinks(content, dir, dir, func(target string) (string, bool, bool) {
+			if target == e.Path() {
+				found = true
+			}
+			return target, false, false
+		})
+		if found {
+			linking = append(linking, other)
+		}
+	}
+	return linking, nil
+}
+
+// MoveSection moves a section to another group and/or name within its layer
+// and updates relative links to it in the layer's other sections, as well as
+// the section's own links if it changed directory. Inside a git work tree the
+// file is moved with "git mv". It returns the new entry and the sections whose
+// links were updated. Links from other layers, such as the global manual, are
+// not updated.
+func MoveSection(e Entry, group, name string) (Entry, []Entry, error) {
+	to := Entry{Layer: e.Layer, Group: group, Name: name}
+	if e.Layer.Global {
+		to.Group = GlobalGroup
+	}
+	if to.Path() == e.Path() {
+		return Entry{}, nil, fmt.Errorf("%s is already at %s", e.Name, e.RelPath())
+	}
+	if !strings.EqualFold(name, e.Name) {
+		if existing, ok, err := FindInLayer(e.Layer, name); err != nil {
+			return Entry{}, nil, err
+		} else if ok {
+			return Entry{}, nil, &SectionExistsError{Existing: existing}
+		}
+	}
+
+	if err := EnsureDir(filepath.Dir(to.Path())); err != nil {
+		return Entry{}, nil, err
+	}
+	oldPath := e.Path()
+	if err := moveFile(oldPath, to.Path()); err != nil {
+		return Entry{}, nil, err
+	}
+
+	updated, err := updateLinks(e.Layer, func(target string) (string, bool, bool) {
+		if target == oldPath {
+			return to.Path(), false, true
+		}
+		return target, false, false
+	}, &to, oldPath)
+	return to, updated, err
+}
+
+// RemoveSection deletes a section and turns links to it from the layer's other
+// sections into plain text. Inside a git work tree the file is removed with
+// "git rm". It returns the sections whose links were removed. Links from other
+// layers, such as the global manual, are left as they are.
+func RemoveSection(e Entry) ([]Entry, error) {
+	path := e.Path()
+	if err := removeFile(path); err != nil {
+		return nil, err
+	}
+	return updateLinks(e.Layer, func(target string) (string, bool, bool) {
+		return target, target == path, false
+	}, nil, "")
+}
@@ -0,0 +1,160 @@
+package fs
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRewriteLinks(t *testing.T) {
+	dir := filepath.FromSlash("/m/.pm/core")
+	old := filepath.FromSlash("/m/.pm/core/vpn.md")
+	moved := filepath.FromSlash("/m/.pm/infra/vpn.md")
+	content := strings.Join([]string{
+		"See [VPN](vpn.md) and [setup](./vpn.md#setup \"Setup\").",
+		"Other: [deploy](deploy.md), [site](https://example.com/vpn.md), ![diagram](vpn.md).",
+		"[ref]: vpn.md#access",
+	}, "\n")
+
+	got, changed := rewriteLinks(content, dir, dir, func(target string) (string, bool, bool) {
+		if target == old {
+			return moved, false, true
+		}
+		return target, false, false
+	})
+	if !changed {
+		t.Fatal("expected content to change")
+	}
+	want := strings.Join([]string{
+		"See [VPN](../infra/vpn.md) and [setup](../infra/vpn.md#setup \"Setup\").",
+		"Other: [deploy](deploy.md), [site](https://example.com/vpn.md), ![diagram](../infra/vpn.md).",
+		"[ref]: ../infra/vpn.md#access",
+	}, "\n")
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	got, _ = rewriteLinks(content, dir, dir, func(target string) (string, bool, bool) {
+		return target, target == old, false
+	})
+	if !strings.HasPrefix(got, "See VPN and setup.") {
+		t.Errorf("expected links to be unlinked, got:\n%s", got)
+	}
+	if !strings.Contains(got, "![diagram](vpn.md)") {
+		t.Errorf("expected images to be kept, got:\n%s", got)
+	}
+}
+
+func TestMoveSection(t *testing.T) {
+	dir := setupTestPM(t)
+	writeTestFile(t, dir, "core/vpn.md", "# VPN\n\nSee [deploy](deploy.md).\n")
+	writeTestFile(t, dir, "core/deploy.md", "# Deploy\n\nConnect to the [VPN](vpn.md#connect) first.\n")
+	writeTestFile(t, dir, "custom/notes.md", "[vpn]: ../core/vpn.md\n")
+	layer := Layer{Name: RootLayer, Root: dir}
+	vpn := Entry{Layer: layer, Group: "core", Name: "vpn"}
+
+	to, updated, err := MoveSection(vpn, "infra", "vpn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if to.RelPath() != filepath.Join("infra", "vpn.md") {
+		t.Errorf("unexpected path %s", to.RelPath())
+	}
+	if _, err := os.Stat(vpn.Path()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected old file to be gone, got %v", err)
+	}
+	if len(updated) != 3 {
+		t.Errorf("expected 3 updated sections, got %v", updated)
+	}
+
+	for rel, want := range map[string]string{
+		"core/deploy.md":  "[VPN](../infra/vpn.md#connect)",
+		"custom/notes.md": "[vpn]: ../infra/vpn.md",
+		"infra/vpn.md":    "[deploy](../core/deploy.md)",
+	} {
+		if content, _ := ReadFile(dir, rel); !strings.Contains(content, want) {
+			t.Errorf("%s: expected %q, got:\n%s", rel, want, content)
+		}
+	}
+
+	// Renaming keeps the group and leaves the section's own links alone.
+	to, _, err = MoveSection(to, "infra", "vpn-access")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := ReadFile(dir, "core/deploy.md"); !strings.Contains(content, "[VPN](../infra/vpn-access.md#connect)") {
+		t.Errorf("expected link to renamed section, got:\n%s", content)
+	}
+	if content, _ := to.Read(); !strings.Contains(content, "[deploy](../core/deploy.md)") {
+		t.Errorf("expected own links to be unchanged, got:\n%s", content)
+	}
+
+	_, _, err = MoveSection(to, "infra", "deploy")
+	var exists *SectionExistsError
+	if !errors.As(err, &exists) {
+		t.Errorf("expected *SectionExistsError, got %v", err)
+	}
+}
+
+func TestMoveSection_Git(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := setupTestPM(t)
+	writeTestFile(t, dir, "core/vpn.md", "# VPN\n")
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=pm", "-c", "user.email=pm@example.com", "commit", "--quiet", "-m", "init"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	vpn := Entry{Layer: Layer{Name: RootLayer, Root: dir}, Group: "core", Name: "vpn"}
+	if _, _, err := MoveSection(vpn, "infra", "vpn"); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command("git", "-C", dir, "status", "--porcelain").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "R  .pm/core/vpn.md -> .pm/infra/vpn.md") {
+		t.Errorf("expected a staged rename, got:\n%s", out)
+	}
+}
+
+func TestRemoveSection(t *testing.T) {
+	dir := setupTestPM(t)
+	writeTestFile(t, dir, "core/vpn.md", "# VPN\n")
+	writeTestFile(t, dir, "core/deploy.md", "Connect to the [VPN](vpn.md) first.\n")
+	writeTestFile(t, dir, "custom/notes.md", "Nothing to see.\n")
+	layer := Layer{Name: RootLayer, Root: dir}
+	vpn := Entry{Layer: layer, Group: "core", Name: "vpn"}
+
+	linking, err := LinksTo(vpn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(linking) != 1 || linking[0].Name != "deploy" {
+		t.Errorf("expected deploy to link to vpn, got %v", linking)
+	}
+
+	updated, err := RemoveSection(vpn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(vpn.Path()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected file to be removed, got %v", err)
+	}
+	if len(updated) != 1 {
+		t.Errorf("expected 1 updated section, got %v", updated)
+	}
+	if content, _ := ReadFile(dir, "core/deploy.md"); content != "Connect to the VPN first.\n" {
+		t.Errorf("unexpected content %q", content)
+	}
+}
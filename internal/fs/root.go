@@ -0,0 +1,57 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// RootEnv names the environment variable that overrides root discovery.
+const RootEnv = "PM_ROOT"
+
+// FindRoot walks up from start looking for a directory that contains .pm/.
+// The walk stops at the filesystem root or at the top of a git work tree
+// (a directory containing .git), so a manual outside the repository is never picked up.
+func FindRoot(start string) (string, bool) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", false
+	}
+
+	for {
+		if DetectPMDir(dir) {
+			return dir, true
+		}
+		if isGitRoot(dir) {
+			return "", false
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// ResolveRoot returns the project root to operate on.
+// An explicit override (e.g. the --root flag) wins, then $PM_ROOT, then FindRoot from start.
+// The returned bool is false when the chosen directory has no .pm/.
+func ResolveRoot(override, start string) (string, bool) {
+	if override == "" {
+		override = os.Getenv(RootEnv)
+	}
+	if override != "" {
+		dir, err := filepath.Abs(override)
+		if err != nil {
+			return "", false
+		}
+		return dir, DetectPMDir(dir)
+	}
+	return FindRoot(start)
+}
+
+// isGitRoot reports whether dir is the top of a git work tree.
+// .git may be a directory or, for worktrees and submodules, a file.
+func isGitRoot(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
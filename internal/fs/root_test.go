@@ -0,0 +1,75 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindRoot(t *testing.T) {
+	dir := setupTestPM(t)
+	nested := filepath.Join(dir, "services", "api")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("from root", func(t *testing.T) {
+		root, ok := FindRoot(dir)
+		if !ok || root != dir {
+			t.Errorf("expected %s, got %s (found=%v)", dir, root, ok)
+		}
+	})
+
+	t.Run("from nested dir", func(t *testing.T) {
+		root, ok := FindRoot(nested)
+		if !ok || root != dir {
+			t.Errorf("expected %s, got %s (found=%v)", dir, root, ok)
+		}
+	})
+
+	t.Run("stops at git boundary", func(t *testing.T) {
+		repo := filepath.Join(dir, "vendor", "repo")
+		if err := os.MkdirAll(filepath.Join(repo, ".git"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if root, ok := FindRoot(repo); ok {
+			t.Errorf("expected no root inside nested git repo, got %s", root)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		if root, ok := FindRoot(t.TempDir()); ok {
+			t.Errorf("expected no root, got %s", root)
+		}
+	})
+}
+
+func TestResolveRoot(t *testing.T) {
+	dir := setupTestPM(t)
+	other := t.TempDir()
+
+	t.Run("override", func(t *testing.T) {
+		root, ok := ResolveRoot(dir, other)
+		if !ok || root != dir {
+			t.Errorf("expected %s, got %s (found=%v)", dir, root, ok)
+		}
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv(RootEnv, dir)
+		root, ok := ResolveRoot("", other)
+		if !ok || root != dir {
+			t.Errorf("expected %s, got %s (found=%v)", dir, root, ok)
+		}
+	})
+
+	t.Run("override without .pm", func(t *testing.T) {
+		root, ok := ResolveRoot(other, dir)
+		if ok {
+			t.Error("expected override without .pm/ to report not found")
+		}
+		if root != other {
+			t.Errorf("expected %s, got %s", other, root)
+		}
+	})
+}
@@ -0,0 +1,295 @@
+package fs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/hojooneum/pm/internal/manual"
+)
+
+// SearchResult represents a single match from a keyword search.
+type SearchResult struct {
+	Layer    string   // layer name; empty for single-manual searches
+	Score    float64  // relevance of the section, set by RankedSearch
+	File     string   // relative path within .pm/, e.g. "core/deploy.md"
+	Title    string   // section title from frontmatter, if any
+	Line     int      // 1-based line number
+	Content  string   // matched line content (trimmed)
+	Headings []string // enclosing markdown headings, outermost first
+	Before   []string // context lines preceding the match (see SearchOptions.Before)
+	After    []string // context lines following the match (see SearchOptions.After)
+}
+
+// SearchOptions controls how query terms are matched.
+type SearchOptions struct {
+	Regex         bool // terms are regular expressions rather than literal text
+	Word          bool // terms only match whole words
+	CaseSensitive bool // terms match case-sensitively
+	Section       bool // evaluate the query against whole sections instead of single lines
+	Before        int  // lines of context to include before each match
+	After         int  // lines of context to include after each match
+}
+
+// Query is a boolean search in disjunctive form: it matches when any of its
+// clauses matches, and a clause matches when all of its terms do.
+type Query struct {
+	Clauses [][]Term
+}
+
+// Term is a single search term, possibly negated.
+type Term struct {
+	Text   string
+	Negate bool
+}
+
+// ParseQuery builds a query from command-line words.
+// Words are AND'ed together; "OR" starts a new alternative. A word prefixed
+// with "-" (or preceded by "NOT") must not match; "+" and "AND" are accepted
+// for readability. For example: postgres -replica, or: timeout OR deadline.
+func ParseQuery(words []string) (Query, error) {
+	var q Query
+	var clause []Term
+	negateNext := false
+
+	closeClause := func() error {
+		if len(clause) == 0 {
+			return fmt.Errorf("empty alternative around OR")
+		}
+		positive := false
+		for _, t := range clause {
+			positive = positive || !t.Negate
+		}
+		if !positive {
+			return fmt.Errorf("each alternative needs at least one term that must match")
+		}
+		q.Clauses = append(q.Clauses, clause)
+		clause = nil
+		return nil
+	}
+
+	for _, w := range words {
+		switch {
+		case w == "OR":
+			if negateNext {
+				return Query{}, fmt.Errorf("NOT must be followed by a term")
+			}
+			if err := closeClause(); err != nil {
+				return Query{}, err
+			}
+			continue
+		case w == "AND":
+			continue
+		case w == "NOT":
+			negateNext = true
+			continue
+		}
+
+		t := Term{Text: w, Negate: negateNext}
+		negateNext = false
+		if len(w) > 1 && (w[0] == '-' || w[0] == '+') {
+			t.Text = w[1:]
+			t.Negate = t.Negate || w[0] == '-'
+		}
+		if t.Text == "" {
+			continue
+		}
+		clause = append(clause, t)
+	}
+
+	if negateNext {
+		return Query{}, fmt.Errorf("NOT must be followed by a term")
+	}
+	if err := closeClause(); err != nil {
+		return Query{}, err
+	}
+	return q, nil
+}
+
+// Matcher is a compiled query.
+type Matcher struct {
+	clauses [][]termMatcher
+	section bool
+	before  int
+	after   int
+
+	// highlightOnly selects the highlighted lines of every file without
+	// evaluating the query, for callers that have already matched the file.
+	highlightOnly bool
+}
+
+type termMatcher struct {
+	re     *regexp.Regexp
+	negate bool
+}
+
+// NewMatcher compiles a query with the given options.
+func NewMatcher(q Query, opts SearchOptions) (*Matcher, error) {
+	m := &Matcher{section: opts.Section, before: opts.Before, after: opts.After}
+	for _, clause := range q.Clauses {
+		var terms []termMatcher
+		for _, t := range clause {
+			expr := t.Text
+			if !opts.Regex {
+				expr = regexp.QuoteMeta(expr)
+			}
+			if opts.Word {
+				expr = `\b(?:` + expr + `)\b`
+			}
+			if !opts.CaseSensitive {
+				expr = `(?i)` + expr
+			}
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", t.Text, err)
+			}
+			terms = append(terms, termMatcher{re: re, negate: t.Negate})
+		}
+		m.clauses = append(m.clauses, terms)
+	}
+	return m, nil
+}
+
+// matches reports whether text satisfies the query.
+func (m *Matcher) matches(text string) bool {
+	for _, clause := range m.clauses {
+		ok := true
+		for _, t := range clause {
+			if t.re.MatchString(text) == t.negate {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// highlights reports whether line contains any positive term, used to pick
+// the lines to show for a section-level match.
+func (m *Matcher) highlights(line string) bool {
+	for _, clause := range m.clauses {
+		for _, t := range clause {
+			if !t.negate && t.re.MatchString(line) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// match returns the 0-based indexes of the matching lines of a file.
+// In section mode the query is evaluated against the whole file, and the
+// lines containing a wanted term are returned if it matches.
+func (m *Matcher) match(lines []string) []int {
+	var hits []int
+	if m.section {
+		if !m.highlightOnly && !m.matches(strings.Join(lines, "\n")) {
+			return nil
+		}
+		for i, line := range lines {
+			if m.highlights(line) {
+				hits = append(hits, i)
+			}
+		}
+		return hits
+	}
+
+	for i, line := range lines {
+		if m.matches(line) {
+			hits = append(hits, i)
+		}
+	}
+	return hits
+}
+
+// SearchLayers runs a query over the sections of every layer.
+// Results are tagged with their layer name when more than one layer is searched.
+func SearchLayers(layers []Layer, m *Matcher) ([]SearchResult, error) {
+	var results []SearchResult
+	for _, l := range layers {
+		groups, err := l.Groups()
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range groups {
+			names, err := l.Files(g)
+			if err != nil {
+				return nil, err
+			}
+			for _, n := range names {
+				e := Entry{Layer: l, Group: g, Name: n}
+				hits, err := searchFile(e.Path(), e.RelPath(), m)
+				if err != nil {
+					return nil, err
+				}
+				if len(layers) > 1 {
+					for i := range hits {
+						hits[i].Layer = l.Name
+					}
+				}
+				results = append(results, hits...)
+			}
+		}
+	}
+	return results, nil
+}
+
+// searchFile returns the lines of the file at path selected by m, annotated
+// with the section title, enclosing headings and context lines.
+// rel is recorded as the result's File.
+func searchFile(path, rel string, m *Matcher) ([]SearchResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	hits := m.match(lines)
+	if len(hits) == 0 {
+		return nil, nil
+	}
+
+	// Headings are parsed from the body only; their line numbers are shifted
+	// back to file lines.
+	fm := manual.FrontmatterLines(lines)
+	headings := manual.ParseHeadings(strings.Join(lines[fm:], "\n"))
+	for i := range headings {
+		headings[i].Line += fm
+	}
+
+	// Malformed frontmatter only costs the title here; pm list reports it.
+	s, _ := manual.ParseSection("", "", strings.Join(lines, "\n"))
+
+	var results []SearchResult
+	for _, i := range hits {
+		// Context stops at the frontmatter, unless the match is inside it.
+		lo := max(0, i-m.before)
+		if i >= fm {
+			lo = max(lo, fm)
+		}
+		results = append(results, SearchResult{
+			File:     rel,
+			Title:    s.Title,
+			Line:     i + 1,
+			Content:  strings.TrimSpace(lines[i]),
+			Headings: manual.HeadingPath(headings, i+1),
+			Before:   lines[lo:i],
+			After:    lines[i+1 : min(len(lines), i+1+m.after)],
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,204 @@
+package fs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		words   []string
+		clauses int
+		wantErr bool
+	}{
+		{"single", []string{"postgres"}, 1, false},
+		{"and with negation", []string{"postgres", "-replica"}, 1, false},
+		{"not keyword", []string{"postgres", "NOT", "replica"}, 1, false},
+		{"or", []string{"timeout", "OR", "deadline"}, 2, false},
+		{"only negation", []string{"-replica"}, 0, true},
+		{"dangling or", []string{"timeout", "OR"}, 0, true},
+		{"dangling not", []string{"timeout", "NOT"}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := ParseQuery(tt.words)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %+v", q)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(q.Clauses) != tt.clauses {
+				t.Errorf("expected %d clauses, got %+v", tt.clauses, q.Clauses)
+			}
+		})
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	lines := []string{
+		"Connect to postgres primary",
+		"postgres replica lag",
+		"Check the Postgresql version",
+		"mysql fallback",
+	}
+
+	tests := []struct {
+		name  string
+		words []string
+		opts  SearchOptions
+		want  []int
+	}{
+		{"substring case-insensitive", []string{"postgres"}, SearchOptions{}, []int{0, 1, 2}},
+		{"case sensitive", []string{"Postgres"}, SearchOptions{CaseSensitive: true}, []int{2}},
+		{"whole word", []string{"postgres"}, SearchOptions{Word: true}, []int{0, 1}},
+		{"negation", []string{"postgres", "-replica"}, SearchOptions{}, []int{0, 2}},
+		{"or", []string{"replica", "OR", "mysql"}, SearchOptions{}, []int{1, 3}},
+		{"regex", []string{`^(mysql|postgres) `}, SearchOptions{Regex: true}, []int{1, 3}},
+		{"section and", []string{"primary", "mysql"}, SearchOptions{Section: true}, []int{0, 3}},
+		{"section excluded", []string{"primary", "-mysql"}, SearchOptions{Section: true}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := ParseQuery(tt.words)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m, err := NewMatcher(q, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := m.match(lines)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewMatcher_InvalidRegex(t *testing.T) {
+	q, _ := ParseQuery([]string{"("})
+	if _, err := NewMatcher(q, SearchOptions{Regex: true}); err == nil {
+		t.Error("expected error for invalid regex")
+	}
+}
+
+func TestSearchLayers(t *testing.T) {
+	dir := setupTestPM(t)
+	writeTestFile(t, dir, "core/deploy.md", "line 1\nTODO: fix this\nline 3")
+	writeTestFile(t, dir, "core/backup.md", "no match here")
+	writeTestFile(t, dir, "custom/app.md", "another TODO item")
+	layers := []Layer{{Name: RootLayer, Root: dir}}
+
+	search := func(words ...string) []SearchResult {
+		t.Helper()
+		q, err := ParseQuery(words)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := NewMatcher(q, SearchOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		results, err := SearchLayers(layers, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results
+	}
+
+	results := search("TODO")
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].File != "core/deploy.md" || results[0].Line != 2 {
+		t.Errorf("expected core/deploy.md line 2, got %s line %d", results[0].File, results[0].Line)
+	}
+	if results := search("todo"); len(results) != 2 {
+		t.Errorf("expected case-insensitive match, got %d results", len(results))
+	}
+
+	if results := search("TODO", "-item"); len(results) != 1 || results[0].File != "core/deploy.md" {
+		t.Errorf("expected the excluded term to drop custom/app.md, got %+v", results)
+	}
+	if results := search("TODO", "NOT", "fix"); len(results) != 1 || results[0].File != "custom/app.md" {
+		t.Errorf("expected NOT to drop core/deploy.md, got %+v", results)
+	}
+	if results := search("fix this"); len(results) != 1 || results[0].Line != 2 {
+		t.Errorf("expected the phrase to match one line, got %+v", results)
+	}
+	if results := search("this fix"); len(results) != 0 {
+		t.Errorf("expected words out of order not to match the phrase, got %+v", results)
+	}
+	if results := search("line 3", "OR", "no match"); len(results) != 2 {
+		t.Errorf("expected either alternative to match, got %+v", results)
+	}
+}
+
+func TestSearchLayers_ContextAndHeadings(t *testing.T) {
+	dir := setupTestPM(t)
+	writeTestFile(t, dir, "core/deploy.md", `---
+title: Deployment Guide
+tags: deploy
+---
+
+# Deployment Guide
+
+## Rollback
+
+### Kubernetes
+
+before
+kubectl rollout undo
+after`)
+	layers := []Layer{{Name: RootLayer, Root: dir}}
+
+	q, _ := ParseQuery([]string{"rollout"})
+	m, err := NewMatcher(q, SearchOptions{Before: 1, After: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := SearchLayers(layers, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+
+	r := results[0]
+	if r.Title != "Deployment Guide" {
+		t.Errorf("expected title from frontmatter, got %q", r.Title)
+	}
+	if r.Line != 13 {
+		t.Errorf("expected line 13, got %d", r.Line)
+	}
+	if got := strings.Join(r.Headings, " > "); got != "Deployment Guide > Rollback > Kubernetes" {
+		t.Errorf("unexpected headings: %q", got)
+	}
+	if len(r.Before) != 1 || r.Before[0] != "before" {
+		t.Errorf("unexpected before context: %q", r.Before)
+	}
+	if len(r.After) != 1 || r.After[0] != "after" {
+		t.Errorf("unexpected after context: %q", r.After)
+	}
+
+	// A match inside the frontmatter must not panic on context bounds.
+	q, _ = ParseQuery([]string{"tags"})
+	m, _ = NewMatcher(q, SearchOptions{Before: 3})
+	if _, err := SearchLayers(layers, m); err != nil {
+		t.Fatal(err)
+	}
+}
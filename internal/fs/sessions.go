@@ -0,0 +1,269 @@
+package fs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SessionsDir is the hidden directory inside a manual where pm run records
+// its sessions, one JSON Lines file per run.
+const SessionsDir = ".sessions"
+
+// Session event types, in the order they appear in a log.
+const (
+	EventStart = "start" // the run began; describes the section and parameters
+	EventStep  = "step"  // a step was run or skipped
+	EventEnd   = "end"   // the run finished; Status says how
+)
+
+// Step actions recorded by step events.
+const (
+	ActionRun  = "run"
+	ActionSkip = "skip"
+)
+
+// Session statuses recorded by the end event. A log without an end event
+// belongs to a run that is still going or was killed.
+const (
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+	StatusAborted   = "aborted"
+)
+
+// SessionEvent is one line of a session log. Which fields are set depends on Type.
+type SessionEvent struct {
+	Type string    `json:"type" yaml:"type"`
+	Time time.Time `json:"time" yaml:"time"` // when the event happened; for steps, when the step started
+
+	// start
+	Section string            `json:"section,omitempty" yaml:"section,omitempty"` // group/name
+	Heading string            `json:"heading,omitempty" yaml:"heading,omitempty"` // slug, when a single heading was run
+	Layer   string            `json:"layer,omitempty" yaml:"layer,omitempty"`
+	User    string            `json:"user,omitempty" yaml:"user,omitempty"`
+	Host    string            `json:"host,omitempty" yaml:"host,omitempty"`
+	Params  map[string]string `json:"params,omitempty" yaml:"params,omitempty"` // secret values are redacted
+
+	// step
+	Step      int        `json:"step,omitempty" yaml:"step,omitempty"` // 1-based
+	Total     int        `json:"total,omitempty" yaml:"total,omitempty"`
+	Headings  []string   `json:"headings,omitempty" yaml:"headings,omitempty"`
+	Command   string     `json:"command,omitempty" yaml:"command,omitempty"` // with parameters substituted, secrets redacted
+	Action    string     `json:"action,omitempty" yaml:"action,omitempty"`   // ActionRun or ActionSkip
+	Edited    bool       `json:"edited,omitempty" yaml:"edited,omitempty"`   // the command was edited before running
+	End       *time.Time `json:"end,omitempty" yaml:"end,omitempty"`
+	ExitCode  *int       `json:"exit_code,omitempty" yaml:"exit_code,omitempty"`
+	Output    string     `json:"output,omitempty" yaml:"output,omitempty"` // combined stdout and stderr, possibly truncated; only with pm run --log-output
+	Truncated bool       `json:"truncated,omitempty" yaml:"truncated,omitempty"`
+
+	// end
+	Status string `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// Session summarizes a session log for listing.
+type Session struct {
+	ID      string // log file name without extension, e.g. 20261018T142501Z-deploy
+	Section string
+	Heading string
+	User    string
+	Started time.Time
+	Status  string // "" while running, or if the run was killed
+	Ran     int    // steps run
+	Skipped int    // steps skipped
+}
+
+// SessionNotFoundError is returned when no session log matches an ID.
+type SessionNotFoundError struct {
+	ID string
+}
+
+func (e *SessionNotFoundError) Error() string {
+	return fmt.Sprintf("session %q not found", e.ID)
+}
+
+// SessionLog appends events to a session log file.
+type SessionLog struct {
+	ID string
+	f  *os.File
+}
+
+// CreateSession starts a new session log in the layer's .sessions directory
+// and writes start, which should be an EventStart event, to it. The directory
+// is kept out of version control, as logs may hold command output.
+func CreateSession(l Layer, start SessionEvent) (*SessionLog, error) {
+	dir := filepath.Join(l.Dir(), SessionsDir)
+	if err := ensureIgnoredDir(dir); err != nil {
+		return nil, err
+	}
+
+	name := start.Section[strings.LastIndex(start.Section, "/")+1:]
+	base := start.Time.UTC().Format("20060102T150405Z") + "-" + name
+	id := base
+	var f *os.File
+	for n := 2; ; n++ {
+		var err error
+		f, err = os.OpenFile(filepath.Join(dir, id+".jsonl"), os.O_WRONLY|os.O_CREATE|os.O_EXCL|os.O_APPEND, 0o644)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		id = fmt.Sprintf("%s-%d", base, n) // another run of the section started within the second
+	}
+
+	s := &SessionLog{ID: id, f: f}
+	if err := s.Append(start); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Append writes an event as one line. Each event is written straight to the
+// file, so the log survives pm being killed mid-run.
+func (s *SessionLog) Append(e SessionEvent) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = s.f.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the log file.
+func (s *SessionLog) Close() error {
+	return s.f.Close()
+}
+
+// ReadSession returns the events of a session. id may be a full session ID or
+// a unique prefix of one; an empty id selects the most recent session.
+func ReadSession(l Layer, id string) (string, []SessionEvent, error) {
+	ids, err := sessionIDs(l)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var match []string
+	for _, candidate := range ids {
+		if candidate == id {
+			match = []string{candidate}
+			break
+		}
+		if strings.HasPrefix(candidate, id) {
+			match = append(match, candidate)
+		}
+	}
+	switch {
+	case id == "" && len(ids) == 0:
+		return "", nil, errors.New("no sessions recorded yet; they are written by pm run")
+	case id == "":
+		sessions, err := ListSessions(l)
+		if err != nil {
+			return "", nil, err
+		}
+		id = sessions[len(sessions)-1].ID
+	case len(match) == 1:
+		id = match[0]
+	case len(match) > 1:
+		return "", nil, fmt.Errorf("session %q is ambiguous (candidates: %s)", id, strings.Join(match, ", "))
+	default:
+		return "", nil, &SessionNotFoundError{ID: id}
+	}
+
+	events, err := readEvents(filepath.Join(l.Dir(), SessionsDir, id+".jsonl"))
+	return id, events, err
+}
+
+// ListSessions summarizes the layer's session logs, oldest first.
+func ListSessions(l Layer) ([]Session, error) {
+	ids, err := sessionIDs(l)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]Session, 0, len(ids))
+	for _, id := range ids {
+		events, err := readEvents(filepath.Join(l.Dir(), SessionsDir, id+".jsonl"))
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, SummarizeSession(id, events))
+	}
+	// IDs only have second precision; runs started within the same second
+	// are ordered by their recorded start time.
+	sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].Started.Before(sessions[j].Started) })
+	return sessions, nil
+}
+
+// SummarizeSession condenses a session's events.
+func SummarizeSession(id string, events []SessionEvent) Session {
+	s := Session{ID: id}
+	for _, e := range events {
+		switch e.Type {
+		case EventStart:
+			s.Section, s.Heading, s.User, s.Started = e.Section, e.Heading, e.User, e.Time
+		case EventStep:
+			if e.Action == ActionSkip {
+				s.Skipped++
+			} else {
+				s.Ran++
+			}
+		case EventEnd:
+			s.Status = e.Status
+		}
+	}
+	return s
+}
+
+// sessionIDs returns the IDs of the layer's session logs, sorted. IDs start
+// with the UTC start time, so this roughly orders sessions by when they started.
+func sessionIDs(l Layer) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(l.Dir(), SessionsDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".jsonl") {
+			ids = append(ids, strings.TrimSuffix(e.Name(), ".jsonl"))
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// readEvents parses a session log. A truncated last line, left by a run that
+// was killed mid-write, is ignored.
+func readEvents(path string) ([]SessionEvent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	var events []SessionEvent
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var e SessionEvent
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			if i == len(lines)-1 {
+				break
+			}
+			return nil, fmt.Errorf("%s: line %d: %w", path, i+1, err)
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
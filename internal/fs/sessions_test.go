@@ -0,0 +1,145 @@
+package fs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateSession(t *testing.T) {
+	l := Layer{Name: RootLayer, Root: setupTestPM(t)}
+	start := time.Date(2026, 10, 18, 14, 25, 1, 0, time.UTC)
+
+	log, err := CreateSession(l, SessionEvent{Type: EventStart, Time: start, Section: "core/deploy", Params: map[string]string{"env": "prod"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if log.ID != "20261018T142501Z-deploy" {
+		t.Errorf("got ID %q", log.ID)
+	}
+	if data, err := os.ReadFile(filepath.Join(l.Dir(), SessionsDir, ".gitignore")); err != nil || string(data) != "*\n" {
+		t.Errorf("expected the sessions directory to be ignored, got %q, %v", data, err)
+	}
+	code := 1
+	for _, e := range []SessionEvent{
+		{Type: EventStep, Time: start.Add(time.Second), Step: 1, Total: 2, Command: "make", Action: ActionRun, ExitCode: &code},
+		{Type: EventStep, Time: start.Add(2 * time.Second), Step: 2, Total: 2, Command: "make test", Action: ActionSkip},
+		{Type: EventEnd, Time: start.Add(3 * time.Second), Status: StatusCompleted},
+	} {
+		if err := log.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := log.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A second run of the section in the same second gets its own log.
+	again, err := CreateSession(l, SessionEvent{Type: EventStart, Time: start.Add(500 * time.Millisecond), Section: "core/deploy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	again.Close()
+	if again.ID != "20261018T142501Z-deploy-2" {
+		t.Errorf("got ID %q for a second session", again.ID)
+	}
+
+	id, events, err := ReadSession(l, "20261018T142501Z-deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != log.ID || len(events) != 4 {
+		t.Fatalf("got %s with %d events", id, len(events))
+	}
+	if events[0].Params["env"] != "prod" || *events[1].ExitCode != 1 || events[2].Action != ActionSkip {
+		t.Errorf("events did not round-trip: %+v", events)
+	}
+
+	s := SummarizeSession(id, events)
+	want := Session{ID: log.ID, Section: "core/deploy", Started: start, Status: StatusCompleted, Ran: 1, Skipped: 1}
+	if !s.Started.Equal(want.Started) {
+		t.Errorf("got start %v, want %v", s.Started, want.Started)
+	}
+	s.Started = want.Started
+	if s != want {
+		t.Errorf("got %+v, want %+v", s, want)
+	}
+}
+
+func TestReadSession(t *testing.T) {
+	l := Layer{Name: RootLayer, Root: setupTestPM(t)}
+
+	if _, _, err := ReadSession(l, ""); err == nil || !strings.Contains(err.Error(), "no sessions") {
+		t.Errorf("expected an error without sessions, got %v", err)
+	}
+
+	start := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	for i, section := range []string{"ops/rollout", "ops/deploy", "ops/restart"} {
+		// rollout and deploy start in the same second, so their IDs sort in
+		// the opposite order to when they started.
+		log, err := CreateSession(l, SessionEvent{Type: EventStart, Time: start.Add(time.Duration(i) * 400 * time.Millisecond), Section: section})
+		if err != nil {
+			t.Fatal(err)
+		}
+		log.Close()
+	}
+
+	sessions, err := ListSessions(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, s := range sessions {
+		ids = append(ids, s.ID)
+	}
+	if got, want := strings.Join(ids, " "), "20261018T090000Z-rollout 20261018T090000Z-deploy 20261018T090000Z-restart"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	if id, _, err := ReadSession(l, ""); err != nil || id != "20261018T090000Z-restart" {
+		t.Errorf("latest: got %q, %v", id, err)
+	}
+	if id, _, err := ReadSession(l, "20261018T090000Z-ro"); err != nil || id != "20261018T090000Z-rollout" {
+		t.Errorf("prefix: got %q, %v", id, err)
+	}
+	if _, _, err := ReadSession(l, "20261018T09"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("expected an ambiguous prefix error, got %v", err)
+	}
+	var notFound *SessionNotFoundError
+	if _, _, err := ReadSession(l, "2025"); !errors.As(err, &notFound) {
+		t.Errorf("expected SessionNotFoundError, got %v", err)
+	}
+}
+
+func TestReadSession_TruncatedLine(t *testing.T) {
+	l := Layer{Name: RootLayer, Root: setupTestPM(t)}
+	dir := filepath.Join(l.Dir(), SessionsDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	log := `{"type":"start","time":"2026-10-18T09:00:00Z","section":"ops/deploy"}
+{"type":"step","time":"2026-10-18T09:00:01Z","step":1,"total":1,"action":"run"}
+{"type":"end","ti`
+	if err := os.WriteFile(filepath.Join(dir, "20261018T090000Z-deploy.jsonl"), []byte(log), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, events, err := ReadSession(l, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := SummarizeSession("x", events); s.Ran != 1 || s.Status != "" {
+		t.Errorf("got %+v, want one step and no status", s)
+	}
+
+	bad := "{\n" + log
+	if err := os.WriteFile(filepath.Join(dir, "20261018T090000Z-deploy.jsonl"), []byte(bad), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ReadSession(l, ""); err == nil {
+		t.Error("expected an error for a corrupt line before the last")
+	}
+}
@@ -0,0 +1,167 @@
+package manual
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CodeBlock is a fenced code block in a section body. Blocks may carry
+// annotations in braces after the language, e.g. ```bash {allow-fail}.
+type CodeBlock struct {
+	Lang  string            // language from the info string, lowercased, e.g. "bash"
+	Attrs map[string]string // annotations; flags such as allow-fail map to ""
+	Code  string            // block content, ending in a newline unless empty
+	Line  int               // 1-based line number of the opening fence
+	Path  []string          // texts of the enclosing headings, outermost first
+}
+
+// Annotations lists the code block annotations pm run understands:
+//
+//   - allow-fail lets the run continue when the block exits non-zero
+//   - destructive asks again before the block runs, defaulting to no
+//   - confirm="phrase" makes the user type phrase before the block runs
+//   - env=name runs the block only in that environment (env=a,b for several)
+var Annotations = []string{"allow-fail", "destructive", "confirm", "env"}
+
+// valueAnnotations are the annotations that take a value; the others are flags.
+var valueAnnotations = map[string]bool{"confirm": true, "env": true}
+
+// shellLangs are the code block languages pm run executes.
+var shellLangs = map[string]bool{"bash": true, "sh": true, "shell": true, "zsh": true}
+
+// IsShell reports whether the block holds shell commands that pm run can execute.
+func (b CodeBlock) IsShell() bool {
+	return shellLangs[b.Lang]
+}
+
+// Has reports whether the block is annotated with attr.
+func (b CodeBlock) Has(attr string) bool {
+	_, ok := b.Attrs[attr]
+	return ok
+}
+
+// Guarded reports whether the block needs an extra confirmation before it runs.
+func (b CodeBlock) Guarded() bool {
+	return b.Has("destructive") || b.Has("confirm")
+}
+
+// Envs returns the environments the block is restricted to by env=, or nil
+// when it runs in any.
+func (b CodeBlock) Envs() []string {
+	if !b.Has("env") {
+		return nil
+	}
+	return strings.Split(b.Attrs["env"], ",")
+}
+
+// CheckAnnotations returns an error for the first annotation not in
+// Annotations, or used with a value when it takes none or vice versa.
+func (b CodeBlock) CheckAnnotations() error {
+	keys := make([]string, 0, len(b.Attrs))
+	for key := range b.Attrs {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		value := b.Attrs[key]
+		switch {
+		case !slices.Contains(Annotations, key):
+			return fmt.Errorf("line %d: unknown annotation %q (known: %s)", b.Line, key, strings.Join(Annotations, ", "))
+		case valueAnnotations[key] && value == "":
+			return fmt.Errorf("line %d: annotation %s needs a value, e.g. %s=prod", b.Line, key, key)
+		case !valueAnnotations[key] && value != "":
+			return fmt.Errorf("line %d: annotation %s takes no value", b.Line, key)
+		case key == "env" && slices.Contains(strings.Split(value, ","), ""):
+			return fmt.Errorf("line %d: invalid environment list %q", b.Line, value)
+		}
+	}
+	return nil
+}
+
+// ParseCodeBlocks returns the fenced code blocks in a markdown body, in order.
+// An unclosed block runs to the end of the body, as in the renderer.
+func ParseCodeBlocks(body string) ([]CodeBlock, error) {
+	headings := ParseHeadings(body)
+
+	var (
+		sc     Scanner
+		blocks []CodeBlock
+		code   strings.Builder
+	)
+	for i, line := range strings.Split(body, "\n") {
+		switch sc.Scan(line) {
+		case FenceOpen:
+			info := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), sc.Fence()))
+			lang, attrs, err := parseInfo(info)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			blocks = append(blocks, CodeBlock{Lang: lang, Attrs: attrs, Line: i + 1, Path: HeadingPath(headings, i+1)})
+			code.Reset()
+		case CodeLine:
+			code.WriteString(line)
+			code.WriteByte('\n')
+		case FenceClose:
+			blocks[len(blocks)-1].Code = code.String()
+		}
+	}
+	if sc.Fence() != "" {
+		blocks[len(blocks)-1].Code = code.String()
+	}
+	return blocks, nil
+}
+
+// parseInfo splits a fence info string such as `bash {confirm="type prod" allow-fail}`
+// into its language and annotations.
+func parseInfo(info string) (string, map[string]string, error) {
+	lang, rest := info, ""
+	if i := strings.IndexByte(info, '{'); i >= 0 {
+		lang, rest = strings.TrimSpace(info[:i]), info[i:]
+	}
+	if fields := strings.Fields(lang); len(fields) > 0 {
+		lang = strings.ToLower(fields[0])
+	}
+	if rest == "" {
+		return lang, nil, nil
+	}
+	if !strings.HasSuffix(rest, "}") {
+		return "", nil, fmt.Errorf("unterminated annotation %q", rest)
+	}
+
+	attrs := make(map[string]string)
+	s := strings.TrimSpace(rest[1 : len(rest)-1])
+	for s != "" {
+		end := strings.IndexAny(s, "= \t")
+		if end < 0 {
+			end = len(s)
+		}
+		key := s[:end]
+		if key == "" {
+			return "", nil, fmt.Errorf("invalid annotation %q", rest)
+		}
+		s = s[end:]
+
+		value := ""
+		if strings.HasPrefix(s, "=") {
+			s = s[1:]
+			if strings.HasPrefix(s, `"`) {
+				closing := strings.IndexByte(s[1:], '"')
+				if closing < 0 {
+					return "", nil, fmt.Errorf("unterminated quote in annotation %q", rest)
+				}
+				value, s = s[1:closing+1], s[closing+2:]
+			} else {
+				end := strings.IndexAny(s, " \t")
+				if end < 0 {
+					end = len(s)
+				}
+				value, s = s[:end], s[end:]
+			}
+		}
+		attrs[key] = value
+		s = strings.TrimSpace(s)
+	}
+	return lang, attrs, nil
+}
@@ -0,0 +1,122 @@
+package manual
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCodeBlocks(t *testing.T) {
+	body := strings.Join([]string{
+		"# Deploy",
+		"",
+		"## Build",
+		"",
+		"```bash",
+		"make build",
+		"make test",
+		"```",
+		"",
+		"## Verify",
+		"",
+		"~~~sh {allow-fail}",
+		"curl -f https://example.com/health",
+		"~~~",
+		"",
+		"```json",
+		`{"ok": true}`,
+		"```",
+		"",
+		"````",
+		"```",
+		"````",
+	}, "\n")
+
+	blocks, err := ParseCodeBlocks(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []CodeBlock{
+		{Lang: "bash", Code: "make build\nmake test\n", Line: 5, Path: []string{"Deploy", "Build"}},
+		{Lang: "sh", Attrs: map[string]string{"allow-fail": ""}, Code: "curl -f https://example.com/health\n", Line: 12, Path: []string{"Deploy", "Verify"}},
+		{Lang: "json", Code: "{\"ok\": true}\n", Line: 16, Path: []string{"Deploy", "Verify"}},
+		{Lang: "", Code: "```\n", Line: 20, Path: []string{"Deploy", "Verify"}},
+	}
+	if !reflect.DeepEqual(blocks, want) {
+		t.Errorf("got %+v\nwant %+v", blocks, want)
+	}
+
+	if !blocks[0].IsShell() || blocks[2].IsShell() {
+		t.Error("expected only shell blocks to be runnable")
+	}
+	if !blocks[1].Has("allow-fail") || blocks[0].Has("allow-fail") {
+		t.Error("expected allow-fail on the second block only")
+	}
+}
+
+func TestParseInfo(t *testing.T) {
+	tests := []struct {
+		info  string
+		lang  string
+		attrs map[string]string
+	}{
+		{"bash", "bash", nil},
+		{"Bash title", "bash", nil},
+		{"bash {allow-fail}", "bash", map[string]string{"allow-fail": ""}},
+		{`sh {confirm="type prod"  env=prod allow-fail}`, "sh", map[string]string{"confirm": "type prod", "env": "prod", "allow-fail": ""}},
+		{"{allow-fail}", "", map[string]string{"allow-fail": ""}},
+	}
+	for _, tt := range tests {
+		lang, attrs, err := parseInfo(tt.info)
+		if err != nil {
+			t.Errorf("%q: %v", tt.info, err)
+			continue
+		}
+		if lang != tt.lang || !reflect.DeepEqual(attrs, tt.attrs) {
+			t.Errorf("%q: got %q %v, want %q %v", tt.info, lang, attrs, tt.lang, tt.attrs)
+		}
+	}
+
+	for _, info := range []string{"bash {allow-fail", `bash {confirm="prod}`, "bash {=x}"} {
+		if _, _, err := parseInfo(info); err == nil {
+			t.Errorf("%q: expected error", info)
+		}
+	}
+}
+
+func TestCheckAnnotations(t *testing.T) {
+	ok := CodeBlock{Attrs: map[string]string{"allow-fail": ""}}
+	if err := ok.CheckAnnotations(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	bad := CodeBlock{Line: 3, Attrs: map[string]string{"allow-fial": ""}}
+	if err := bad.CheckAnnotations(); err == nil || !strings.Contains(err.Error(), "allow-fial") {
+		t.Errorf("expected unknown annotation error, got %v", err)
+	}
+
+	guarded := CodeBlock{Attrs: map[string]string{"destructive": "", "confirm": "type prod", "env": "prod,staging"}}
+	if err := guarded.CheckAnnotations(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !guarded.Guarded() || ok.Guarded() {
+		t.Error("expected only destructive and confirm blocks to be guarded")
+	}
+	if got := guarded.Envs(); !reflect.DeepEqual(got, []string{"prod", "staging"}) {
+		t.Errorf("got envs %v", got)
+	}
+	if ok.Envs() != nil {
+		t.Errorf("expected no env restriction, got %v", ok.Envs())
+	}
+
+	for _, attrs := range []map[string]string{
+		{"confirm": ""},
+		{"env": ""},
+		{"env": "prod,"},
+		{"destructive": "yes"},
+	} {
+		b := CodeBlock{Line: 3, Attrs: attrs}
+		if err := b.CheckAnnotations(); err == nil {
+			t.Errorf("%v: expected error", attrs)
+		}
+	}
+}
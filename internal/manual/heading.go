@@ -0,0 +1,174 @@
+package manual
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Heading is an ATX markdown heading ("## Rollback").
+type Heading struct {
+	Level int    // 1 for "#", 2 for "##", ...
+	Text  string // heading text without the leading #s
+	Slug  string // GitHub-style anchor, unique within the parsed text (see Slugify)
+	Line  int    // 1-based line number within the parsed text
+}
+
+// ParseHeadings returns the ATX headings in a markdown body, in order.
+// Lines inside fenced code blocks are ignored, so shell comments are not
+// mistaken for headings. Repeated slugs get a "-1", "-2", ... suffix, as on GitHub.
+func ParseHeadings(body string) []Heading {
+	var headings []Heading
+	slugs := make(map[string]int)
+	var sc Scanner
+	for i, line := range strings.Split(body, "\n") {
+		if sc.Scan(line) != TextLine {
+			continue
+		}
+
+		if h, ok := ParseHeadingLine(line); ok {
+			h.Line = i + 1
+			h.Slug = Slugify(h.Text)
+			if n := slugs[h.Slug]; n > 0 {
+				slugs[h.Slug]++
+				h.Slug = fmt.Sprintf("%s-%d", h.Slug, n)
+			} else {
+				slugs[h.Slug] = 1
+			}
+			headings = append(headings, h)
+		}
+	}
+	return headings
+}
+
+// HeadingPath returns the texts of the headings enclosing a line, outermost
+// first. A heading line encloses itself.
+func HeadingPath(headings []Heading, line int) []string {
+	var stack []Heading
+	for _, h := range headings {
+		if h.Line > line {
+			break
+		}
+		for len(stack) > 0 && stack[len(stack)-1].Level >= h.Level {
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, h)
+	}
+
+	path := make([]string, len(stack))
+	for i, h := range stack {
+		path[i] = h.Text
+	}
+	return path
+}
+
+// inlineLink matches a markdown link or image, keeping its text.
+var inlineLink = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+
+// Slugify turns heading text into a GitHub-style anchor: link markup is
+// reduced to its text, letters are lowercased, spaces become hyphens, and
+// punctuation other than "-" and "_" is dropped.
+// "Rollback: Kubernetes (v1.29)" becomes "rollback-kubernetes-v129".
+func Slugify(text string) string {
+	text = inlineLink.ReplaceAllString(text, "$1")
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case r == ' ':
+			b.WriteRune('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r):
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// HeadingNotFoundError is returned by ExtractHeading when no heading has the requested slug.
+type HeadingNotFoundError struct {
+	Slug      string
+	Available []string // slugs of all headings in the body
+}
+
+func (e *HeadingNotFoundError) Error() string {
+	if len(e.Available) == 0 {
+		return fmt.Sprintf("heading %q not found (the section has no headings)", e.Slug)
+	}
+	return fmt.Sprintf("heading %q not found (available: %s)", e.Slug, strings.Join(e.Available, ", "))
+}
+
+// ExtractHeading returns the part of a markdown body under the heading with
+// the given slug: the heading line itself and everything up to the next
+// heading of the same or a higher level, so subsections are included.
+// slug may also be the heading text; it is slugified before comparing.
+func ExtractHeading(body, slug string) (Heading, string, error) {
+	headings := ParseHeadings(body)
+	want := strings.ToLower(strings.TrimPrefix(slug, "#"))
+
+	for i, h := range headings {
+		if h.Slug != want && h.Slug != Slugify(want) {
+			continue
+		}
+		lines := strings.Split(body, "\n")
+		end := len(lines)
+		for _, next := range headings[i+1:] {
+			if next.Level <= h.Level {
+				end = next.Line - 1
+				break
+			}
+		}
+		return h, strings.TrimRight(strings.Join(lines[h.Line-1:end], "\n"), "\n") + "\n", nil
+	}
+
+	available := make([]string, len(headings))
+	for i, h := range headings {
+		available[i] = h.Slug
+	}
+	return Heading{}, "", &HeadingNotFoundError{Slug: slug, Available: available}
+}
+
+// FrontmatterLines returns the number of lines taken by a leading frontmatter
+// block, including both "---" delimiters, or 0 if there is none.
+func FrontmatterLines(lines []string) int {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return 0
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// ParseHeadingLine parses a single ATX heading line such as "## Rollback".
+// Level and Text are set; Slug and Line depend on the rest of the text (see
+// ParseHeadings). As in CommonMark, a closing run of "#"s is dropped only when
+// preceded by whitespace, so "## Learn C#" keeps its "#".
+func ParseHeadingLine(line string) (Heading, bool) {
+	// Up to three spaces of indentation are allowed.
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return Heading{}, false
+	}
+
+	level := 0
+	for level < len(trimmed) && trimmed[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return Heading{}, false
+	}
+	rest := trimmed[level:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return Heading{}, false // "#hashtag" is not a heading
+	}
+
+	text := strings.TrimSpace(rest)
+	// Drop an optional closing sequence: "## Title ##"
+	if stripped := strings.TrimRight(text, "#"); stripped != text && (stripped == "" || strings.HasSuffix(stripped, " ") || strings.HasSuffix(stripped, "\t")) {
+		text = strings.TrimSpace(stripped)
+	}
+	return Heading{Level: level, Text: text}, true
+}
@@ -0,0 +1,174 @@
+package manual
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const headingDoc = `# Deployment Guide
+
+## Rollback
+
+` + "```bash" + `
+# not a heading
+kubectl rollout undo
+` + "```" + `
+
+### Kubernetes ###
+
+kubectl rollout undo deployment/api
+
+## Verify
+#hashtag`
+
+func TestParseHeadings(t *testing.T) {
+	headings := ParseHeadings(headingDoc)
+
+	want := []Heading{
+		{Level: 1, Text: "Deployment Guide", Slug: "deployment-guide", Line: 1},
+		{Level: 2, Text: "Rollback", Slug: "rollback", Line: 3},
+		{Level: 3, Text: "Kubernetes", Slug: "kubernetes", Line: 10},
+		{Level: 2, Text: "Verify", Slug: "verify", Line: 14},
+	}
+	if len(headings) != len(want) {
+		t.Fatalf("expected %d headings, got %+v", len(want), headings)
+	}
+	for i := range want {
+		if headings[i] != want[i] {
+			t.Errorf("heading[%d]: expected %+v, got %+v", i, want[i], headings[i])
+		}
+	}
+}
+
+func TestParseHeadingLine(t *testing.T) {
+	tests := []struct {
+		line  string
+		level int
+		text  string
+	}{
+		{"## Rollback", 2, "Rollback"},
+		{"## Learn C#", 2, "Learn C#"},
+		{"## Learn C# ##", 2, "Learn C#"},
+		{"### Kubernetes\t###", 3, "Kubernetes"},
+		{"   # Indented", 1, "Indented"},
+		{"## ##", 2, ""},
+	}
+	for _, tt := range tests {
+		h, ok := ParseHeadingLine(tt.line)
+		if !ok || h.Level != tt.level || h.Text != tt.text {
+			t.Errorf("%q: got %+v, %v; want level %d, %q", tt.line, h, ok, tt.level, tt.text)
+		}
+	}
+	for _, line := range []string{"#hashtag", "    # code", "####### seven", "text"} {
+		if _, ok := ParseHeadingLine(line); ok {
+			t.Errorf("%q: expected no heading", line)
+		}
+	}
+}
+
+func TestHeadingPath(t *testing.T) {
+	headings := ParseHeadings(headingDoc)
+
+	tests := []struct {
+		line int
+		want string
+	}{
+		{12, "Deployment Guide > Rollback > Kubernetes"},
+		{6, "Deployment Guide > Rollback"},
+		{15, "Deployment Guide > Verify"},
+		{1, "Deployment Guide"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(HeadingPath(headings, tt.line), " > "); got != tt.want {
+			t.Errorf("line %d: expected %q, got %q", tt.line, tt.want, got)
+		}
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Rollback", "rollback"},
+		{"Pre-deployment Checklist", "pre-deployment-checklist"},
+		{"1. Prepare Release", "1-prepare-release"},
+		{"Rollback: Kubernetes (v1.29)", "rollback-kubernetes-v129"},
+		{"Backup & Recovery", "backup--recovery"},
+		{"Use `kubectl` [docs](https://k8s.io)", "use-kubectl-docs"},
+		{"snake_case", "snake_case"},
+		{"Überprüfung", "überprüfung"},
+	}
+	for _, tt := range tests {
+		if got := Slugify(tt.in); got != tt.want {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseHeadings_DuplicateSlugs(t *testing.T) {
+	headings := ParseHeadings("## Steps\n## Steps\n## Steps")
+	for i, want := range []string{"steps", "steps-1", "steps-2"} {
+		if headings[i].Slug != want {
+			t.Errorf("heading %d: expected slug %q, got %q", i, want, headings[i].Slug)
+		}
+	}
+}
+
+func TestExtractHeading(t *testing.T) {
+	t.Run("includes subsections", func(t *testing.T) {
+		h, text, err := ExtractHeading(headingDoc, "rollback")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.Text != "Rollback" {
+			t.Errorf("unexpected heading %+v", h)
+		}
+		if !strings.HasPrefix(text, "## Rollback\n") || !strings.Contains(text, "### Kubernetes") || strings.Contains(text, "Verify") {
+			t.Errorf("unexpected text:\n%s", text)
+		}
+	})
+
+	t.Run("last heading runs to the end", func(t *testing.T) {
+		_, text, err := ExtractHeading(headingDoc, "#verify")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text != "## Verify\n#hashtag\n" {
+			t.Errorf("unexpected text: %q", text)
+		}
+	})
+
+	t.Run("heading text", func(t *testing.T) {
+		if _, _, err := ExtractHeading(headingDoc, "Deployment Guide"); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, _, err := ExtractHeading(headingDoc, "missing")
+		var nf *HeadingNotFoundError
+		if !errors.As(err, &nf) {
+			t.Fatalf("expected *HeadingNotFoundError, got %v", err)
+		}
+		if len(nf.Available) != 4 {
+			t.Errorf("expected 4 available slugs, got %v", nf.Available)
+		}
+	})
+}
+
+func TestFrontmatterLines(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want int
+	}{
+		{"---\ntitle: x\n---\nbody", 3},
+		{"# no frontmatter", 0},
+		{"---\nunclosed", 0},
+	}
+	for _, tt := range tests {
+		if got := FrontmatterLines(strings.Split(tt.raw, "\n")); got != tt.want {
+			t.Errorf("%q: expected %d, got %d", tt.raw, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package manual
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Param is a runbook parameter declared in frontmatter. Its placeholders in
+// the body, <name>, $NAME and ${NAME} (see Param.EnvName), are replaced with
+// the value given for it:
+//
+//	params:
+//	  - name: namespace
+//	    description: Kubernetes namespace to deploy to
+//	    default: staging
+//	    values: [staging, production]
+//	  - name: api-token
+//	    secret: true
+//
+// A parameter without a default is required.
+type Param struct {
+	Name        string
+	Description string
+	Default     string
+	HasDefault  bool     // set when a default is given, even ""
+	Values      []string // allowed values; any value when empty
+	Secret      bool     // never printed or logged
+}
+
+// EnvName returns the shell-style name of the parameter: "api-token" becomes "API_TOKEN".
+func (p Param) EnvName() string {
+	return strings.ToUpper(strings.ReplaceAll(p.Name, "-", "_"))
+}
+
+// Check returns an error if value is not one of the parameter's allowed values.
+func (p Param) Check(value string) error {
+	if len(p.Values) > 0 && !slices.Contains(p.Values, value) {
+		return fmt.Errorf("invalid value %q for parameter %s (allowed: %s)", value, p.Name, strings.Join(p.Values, ", "))
+	}
+	return nil
+}
+
+// MissingParamsError is returned when required parameters have no value.
+type MissingParamsError struct {
+	Names []string
+}
+
+func (e *MissingParamsError) Error() string {
+	return fmt.Sprintf("missing required parameter(s): %s", strings.Join(e.Names, ", "))
+}
+
+var paramNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// reservedEnvNames are environment variables a parameter may not set (see
+// Param.EnvName), since steps and the shells running them rely on them.
+var reservedEnvNames = []string{
+	"CDPATH", "DISPLAY", "EDITOR", "EUID", "HOME", "HOSTNAME", "IFS", "LANG",
+	"LOGNAME", "MAIL", "OLDPWD", "PAGER", "PATH", "PPID", "PS1", "PS2", "PS4",
+	"PWD", "SHELL", "SHLVL", "TERM", "TMPDIR", "TZ", "UID", "USER",
+}
+
+// reservedEnvPrefixes are prefixes of reserved environment variables, as for
+// locale, dynamic linker and shell settings and pm's own.
+var reservedEnvPrefixes = []string{"BASH_", "LC_", "LD_", "PM_", "ZSH_"}
+
+// reservedEnvName reports whether a parameter would override an environment
+// variable that steps rely on.
+func reservedEnvName(env string) bool {
+	if slices.Contains(reservedEnvNames, env) {
+		return true
+	}
+	return slices.ContainsFunc(reservedEnvPrefixes, func(prefix string) bool {
+		return strings.HasPrefix(env, prefix)
+	})
+}
+
+// decodeParams decodes the params frontmatter key: a list of parameter
+// mappings, or of bare names for required parameters without a description.
+func decodeParams(n *yaml.Node) ([]Param, error) {
+	if n.Tag == "!!null" {
+		return nil, nil
+	}
+	if n.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("params must be a list")
+	}
+
+	var params []Param
+	for _, item := range n.Content {
+		var p Param
+		switch item.Kind {
+		case yaml.ScalarNode:
+			p.Name = item.Value
+		case yaml.MappingNode:
+			var raw struct {
+				Name        string    `yaml:"name"`
+				Description string    `yaml:"description"`
+				Default     yaml.Node `yaml:"default"`
+				Values      []string  `yaml:"values"`
+				Secret      bool      `yaml:"secret"`
+			}
+			if err := item.Decode(&raw); err != nil {
+				return nil, fmt.Errorf("params: %w", err)
+			}
+			if raw.Default.Kind != 0 && raw.Default.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("params: default of %s must be a single value", raw.Name)
+			}
+			p = Param{
+				Name:        raw.Name,
+				Description: strings.TrimSpace(raw.Description),
+				Default:     raw.Default.Value,
+				HasDefault:  raw.Default.Kind != 0,
+				Values:      raw.Values,
+				Secret:      raw.Secret,
+			}
+			if p.HasDefault {
+				if err := p.Check(p.Default); err != nil {
+					return nil, fmt.Errorf("params: default: %w", err)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("params must be a list of names or name/description mappings")
+		}
+
+		if !paramNamePattern.MatchString(p.Name) {
+			return nil, fmt.Errorf("params: invalid parameter name %q", p.Name)
+		}
+		if reservedEnvName(p.EnvName()) {
+			return nil, fmt.Errorf("params: parameter %s would override $%s; choose another name", p.Name, p.EnvName())
+		}
+		if slices.ContainsFunc(params, func(q Param) bool { return q.EnvName() == p.EnvName() }) {
+			return nil, fmt.Errorf("params: duplicate parameter %s", p.Name)
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+// placeholderPattern matches the placeholders of params in text.
+func placeholderPattern(params []Param) *regexp.Regexp {
+	names := make([]string, len(params))
+	envs := make([]string, len(params))
+	for i, p := range params {
+		names[i] = regexp.QuoteMeta(p.Name)
+		envs[i] = p.EnvName()
+	}
+	n, e := strings.Join(names, "|"), strings.Join(envs, "|")
+	return regexp.MustCompile(`<(` + n + `)>|\$\{(` + e + `)\}|\$(` + e + `)\b`)
+}
+
+// paramFor returns the parameter a placeholder match refers to.
+func paramFor(params []Param, sub []string) Param {
+	for _, p := range params {
+		if sub[1] == p.Name || sub[2] == p.EnvName() || sub[3] == p.EnvName() {
+			return p
+		}
+	}
+	return Param{}
+}
+
+// UsedParams returns the parameters whose placeholders appear in text, in
+// declaration order.
+func UsedParams(text string, params []Param) []Param {
+	if len(params) == 0 {
+		return nil
+	}
+	used := make(map[string]bool)
+	for _, sub := range placeholderPattern(params).FindAllStringSubmatch(text, -1) {
+		used[paramFor(params, sub).Name] = true
+	}
+
+	var out []Param
+	for _, p := range params {
+		if used[p.Name] {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Substitute replaces the placeholders of params in text with their values.
+// Parameters missing from values are left as they are.
+func Substitute(text string, params []Param, values map[string]string) string {
+	if len(params) == 0 {
+		return text
+	}
+	re := placeholderPattern(params)
+	return re.ReplaceAllStringFunc(text, func(m string) string {
+		if v, ok := values[paramFor(params, re.FindStringSubmatch(m)).Name]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+// SubstituteNames is like Substitute but only replaces <name> placeholders,
+// leaving $NAME and ${NAME} for a shell to expand from the environment.
+func SubstituteNames(text string, params []Param, values map[string]string) string {
+	if len(params) == 0 {
+		return text
+	}
+	re := placeholderPattern(params)
+	return re.ReplaceAllStringFunc(text, func(m string) string {
+		sub := re.FindStringSubmatch(m)
+		if sub[1] == "" {
+			return m
+		}
+		if v, ok := values[sub[1]]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+// CheckParams returns an error if given names a parameter the section does
+// not declare or a value the parameter does not allow.
+func (s Section) CheckParams(given map[string]string) error {
+	for name, v := range given {
+		i := slices.IndexFunc(s.Params, func(p Param) bool { return p.Name == name })
+		if i < 0 {
+			if len(s.Params) == 0 {
+				return fmt.Errorf("unknown parameter %q (%s/%s declares none)", name, s.Group, s.Name)
+			}
+			names := make([]string, len(s.Params))
+			for j, p := range s.Params {
+				names[j] = p.Name
+			}
+			return fmt.Errorf("unknown parameter %q (declared: %s)", name, strings.Join(names, ", "))
+		}
+		if err := s.Params[i].Check(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResolveParams works out the values of params from the values given by the
+// user (see Section.CheckParams) and their defaults. It returns the required
+// parameters still missing a value, which the caller may prompt for.
+func ResolveParams(params []Param, given map[string]string) (values map[string]string, missing []Param) {
+	values = make(map[string]string)
+	for _, p := range params {
+		if v, ok := given[p.Name]; ok {
+			values[p.Name] = v
+		} else if p.HasDefault {
+			values[p.Name] = p.Default
+		} else {
+			missing = append(missing, p)
+		}
+	}
+	return values, missing
+}
@@ -0,0 +1,122 @@
+package manual
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const paramsSection = `---
+title: Rollout
+params:
+  - name: namespace
+    description: Kubernetes namespace
+    values: [staging, prod]
+  - name: version
+    default: 1.2
+  - name: api-token
+    secret: true
+  - dry-run
+---
+Deploy <version> to <namespace> with $API_TOKEN.
+`
+
+func TestParseSection_Params(t *testing.T) {
+	s, err := ParseSection("rollout", "core", paramsSection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Param{
+		{Name: "namespace", Description: "Kubernetes namespace", Values: []string{"staging", "prod"}},
+		{Name: "version", Default: "1.2", HasDefault: true},
+		{Name: "api-token", Secret: true},
+		{Name: "dry-run"},
+	}
+	if !reflect.DeepEqual(s.Params, want) {
+		t.Errorf("got %+v\nwant %+v", s.Params, want)
+	}
+	if _, ok := s.Meta["params"]; ok {
+		t.Error("params should not be kept in Meta")
+	}
+}
+
+func TestParseSection_InvalidParams(t *testing.T) {
+	tests := map[string]string{
+		"not a list":       "params: namespace",
+		"bad name":         "params: [\"9lives\"]",
+		"duplicate":        "params: [api-token, api_token]",
+		"bad default":      "params:\n  - name: env\n    default: dev\n    values: [staging, prod]",
+		"list default":     "params:\n  - name: env\n    default: [a]",
+		"nested list item": "params:\n  - [a, b]",
+		"system variable":  "params: [path]",
+		"shell variable":   "params: [namespace, home]",
+		"reserved prefix":  "params: [ld-preload]",
+	}
+	for name, fm := range tests {
+		if _, err := ParseSection("x", "core", "---\n"+fm+"\n---\nbody"); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestParseSection_EnvLikeParams(t *testing.T) {
+	s, err := ParseSection("x", "core", "---\nparams: [env, user-name, pathname]\n---\nbody")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Params) != 3 {
+		t.Errorf("expected 3 params, got %v", s.Params)
+	}
+}
+
+func TestSubstitute(t *testing.T) {
+	params := []Param{{Name: "namespace"}, {Name: "api-token"}, {Name: "version"}}
+	values := map[string]string{"namespace": "prod", "api-token": "s3cret"}
+	text := "kubectl -n <namespace> ($NAMESPACE, ${NAMESPACE}) $API_TOKEN ${API_TOKEN} $API_TOKENS $PATH <version> <Namespace>"
+
+	got := Substitute(text, params, values)
+	want := "kubectl -n prod (prod, prod) s3cret s3cret $API_TOKENS $PATH <version> <Namespace>"
+	if got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+
+	got = SubstituteNames(text, params, values)
+	want = "kubectl -n prod ($NAMESPACE, ${NAMESPACE}) $API_TOKEN ${API_TOKEN} $API_TOKENS $PATH <version> <Namespace>"
+	if got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+
+	used := UsedParams(text, params)
+	if len(used) != 3 {
+		t.Errorf("expected all params to be used, got %v", used)
+	}
+	if used := UsedParams("just $PATH", params); len(used) != 0 {
+		t.Errorf("expected no params to be used, got %v", used)
+	}
+}
+
+func TestResolveParams(t *testing.T) {
+	s, err := ParseSection("rollout", "core", paramsSection)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, missing := ResolveParams(s.Params, map[string]string{"namespace": "prod"})
+	if values["namespace"] != "prod" || values["version"] != "1.2" {
+		t.Errorf("unexpected values %v", values)
+	}
+	var names []string
+	for _, p := range missing {
+		names = append(names, p.Name)
+	}
+	if strings.Join(names, ",") != "api-token,dry-run" {
+		t.Errorf("unexpected missing params %v", names)
+	}
+
+	for given, ok := range map[string]bool{"namespace=prod": true, "namespace=dev": false, "owner=me": false} {
+		k, v, _ := strings.Cut(given, "=")
+		if err := s.CheckParams(map[string]string{k: v}); (err == nil) != ok {
+			t.Errorf("%s: got error %v", given, err)
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+
+	"go.yaml.in/yaml/v3"
 )
 
 // SectionDef describes a section to scaffold in a template.
@@ -27,6 +29,24 @@ type Template struct {
 
 var sectionNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
 
+// ValidateSectionName checks that name is usable as a section filename:
+// lowercase letters, digits and hyphens, not starting with a hyphen.
+func ValidateSectionName(name string) error {
+	if !sectionNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid section name %q: must match %s (e.g. \"db-failover\")", name, sectionNamePattern.String())
+	}
+	return nil
+}
+
+// ValidateGroupName checks that group is usable as a group directory name.
+// Groups follow the same rules as section names.
+func ValidateGroupName(group string) error {
+	if !sectionNamePattern.MatchString(group) {
+		return fmt.Errorf("invalid group name %q: must match %s", group, sectionNamePattern.String())
+	}
+	return nil
+}
+
 // built-in presets keyed by name
 var builtinPresets = map[string]Template{
 	"default": {
@@ -220,17 +240,78 @@ func GenerateSectionContent(def SectionDef) string {
 		return tmpl
 	}
 
+	var b strings.Builder
+	b.WriteString(frontmatter(def))
+	b.WriteString("# " + def.Title + "\n\n")
+	b.WriteString("<!-- TODO: Document this section -->\n")
+	return b.String()
+}
+
+// frontmatter renders the YAML frontmatter block for a section definition,
+// followed by a blank line.
+func frontmatter(def SectionDef) string {
 	var b strings.Builder
 	b.WriteString("---\n")
-	b.WriteString("title: " + def.Title + "\n")
+	b.WriteString("title: " + yamlScalar(def.Title) + "\n")
 	if def.Description != "" {
-		b.WriteString("description: " + def.Description + "\n")
+		b.WriteString("description: " + yamlScalar(def.Description) + "\n")
 	}
 	if len(def.Tags) > 0 {
-		b.WriteString("tags: " + strings.Join(def.Tags, ", ") + "\n")
+		b.WriteString("tags: " + yamlScalar(strings.Join(def.Tags, ", ")) + "\n")
 	}
 	b.WriteString("---\n\n")
-	b.WriteString("# " + def.Title + "\n\n")
-	b.WriteString("<!-- TODO: Document this section -->\n")
 	return b.String()
 }
+
+// GenerateSectionContentFrom returns markdown content for a section definition
+// using the body of one of the DefaultTemplates (e.g. "troubleshoot") as a
+// starting point. The template's frontmatter is replaced by the definition's;
+// empty fields in def fall back to the template's own title, description and tags.
+// The template's top-level heading is retitled to match.
+func GenerateSectionContentFrom(def SectionDef, template string) (string, error) {
+	raw, ok := DefaultTemplates[template]
+	if !ok {
+		names := make([]string, 0, len(DefaultTemplates))
+		for n := range DefaultTemplates {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("unknown section template %q (available: %s)", template, strings.Join(names, ", "))
+	}
+
+	base, err := ParseSection(template, "", raw)
+	if err != nil {
+		return "", err
+	}
+	if def.Title == "" {
+		def.Title = base.Title
+	}
+	if def.Description == "" {
+		def.Description = base.Description
+	}
+	if len(def.Tags) == 0 {
+		def.Tags = base.Tags
+	}
+
+	lines := strings.Split(base.Body, "\n")
+	for i, line := range lines {
+		if h, ok := ParseHeadingLine(line); ok {
+			if h.Level == 1 {
+				lines[i] = "# " + def.Title
+			}
+			break
+		}
+	}
+
+	return frontmatter(def) + strings.Join(lines, "\n"), nil
+}
+
+// yamlScalar renders s as a YAML scalar, quoting it only when a plain scalar
+// would be misread (e.g. values containing ": " or starting with "&").
+func yamlScalar(s string) string {
+	out, err := yaml.Marshal(s)
+	if err != nil {
+		return s
+	}
+	return strings.TrimSuffix(string(out), "\n")
+}
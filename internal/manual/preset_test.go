@@ -198,7 +198,10 @@ func TestGenerateSectionContent_Parseable(t *testing.T) {
 		Tags:        []string{"custom", "test"},
 	}
 	content := GenerateSectionContent(def)
-	s := ParseSection(def.Name, def.Group, content)
+	s, err := ParseSection(def.Name, def.Group, content)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if s.Title != "Custom Section" {
 		t.Errorf("expected title 'Custom Section', got %q", s.Title)
@@ -360,3 +363,44 @@ func TestResolveTemplate_Empty(t *testing.T) {
 		t.Errorf("expected 'default' for empty input, got %q", tmpl.Name)
 	}
 }
+
+func TestValidateSectionName(t *testing.T) {
+	for _, name := range []string{"deploy", "db-failover", "2fa"} {
+		if err := ValidateSectionName(name); err != nil {
+			t.Errorf("%q: unexpected error %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "Deploy", "-x", "a_b", "../etc", "a/b"} {
+		if err := ValidateSectionName(name); err == nil {
+			t.Errorf("%q: expected error", name)
+		}
+	}
+}
+
+func TestGenerateSectionContentFrom(t *testing.T) {
+	content, err := GenerateSectionContentFrom(SectionDef{Name: "api-incident", Title: "API Incident", Tags: []string{"api"}}, "troubleshoot")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := ParseSection("api-incident", "custom", content)
+	if err != nil {
+		t.Fatalf("generated content does not parse: %v", err)
+	}
+	if s.Title != "API Incident" || len(s.Tags) != 1 || s.Tags[0] != "api" {
+		t.Errorf("unexpected frontmatter: %+v", s)
+	}
+	if s.Description != "Common issues and resolution steps" {
+		t.Errorf("expected the template's description as fallback, got %q", s.Description)
+	}
+	if !strings.HasPrefix(s.Body, "# API Incident\n") {
+		t.Errorf("expected retitled heading, got %q", s.Body[:min(len(s.Body), 40)])
+	}
+	if !strings.Contains(s.Body, "## Common Issues") {
+		t.Error("expected the template's body")
+	}
+
+	if _, err := GenerateSectionContentFrom(SectionDef{Title: "X"}, "nope"); err == nil {
+		t.Error("expected error for an unknown template")
+	}
+}
@@ -0,0 +1,58 @@
+package manual
+
+import "strings"
+
+// LineKind classifies a markdown line by its relation to fenced code blocks.
+type LineKind int
+
+const (
+	TextLine   LineKind = iota // outside any code block
+	FenceOpen                  // opens a fenced code block
+	CodeLine                   // inside a fenced code block
+	FenceClose                 // closes the open code block
+)
+
+// Scanner follows fenced code blocks through a markdown text fed to it line
+// by line, so that headings, task items and the like are only looked for
+// outside code. Its zero value is ready to use.
+type Scanner struct {
+	fence string // marker of the open block, e.g. "```"
+}
+
+// Scan classifies the next line. A block is closed by a line holding only a
+// run of the opening fence's character at least as long as the opening run,
+// as in CommonMark; an unclosed block runs to the end of the text.
+func (s *Scanner) Scan(line string) LineKind {
+	trimmed := strings.TrimSpace(line)
+	if s.fence == "" {
+		if s.fence = FenceMarker(trimmed); s.fence != "" {
+			return FenceOpen
+		}
+		return TextLine
+	}
+	if strings.HasPrefix(trimmed, s.fence) && strings.Trim(trimmed, s.fence[:1]) == "" {
+		s.fence = ""
+		return FenceClose
+	}
+	return CodeLine
+}
+
+// Fence returns the marker of the open code block, or "" outside code.
+func (s *Scanner) Fence() string {
+	return s.fence
+}
+
+// FenceMarker returns the opening run of a trimmed code fence line ("```",
+// "~~~~", ...), or "" if the line is not a fence.
+func FenceMarker(trimmed string) string {
+	for _, c := range []byte{'`', '~'} {
+		n := 0
+		for n < len(trimmed) && trimmed[n] == c {
+			n++
+		}
+		if n >= 3 {
+			return trimmed[:n]
+		}
+	}
+	return ""
+}
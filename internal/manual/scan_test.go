@@ -0,0 +1,39 @@
+package manual
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScanner(t *testing.T) {
+	lines := []string{
+		"text",
+		"````markdown",
+		"```bash",
+		"~~~",
+		"```",
+		"````",
+		"  ~~~ sh",
+		"~~~~~",
+		"```",
+		"unclosed",
+	}
+	want := []LineKind{TextLine, FenceOpen, CodeLine, CodeLine, CodeLine, FenceClose, FenceOpen, FenceClose, FenceOpen, CodeLine}
+
+	var sc Scanner
+	for i, line := range lines {
+		if got := sc.Scan(line); got != want[i] {
+			t.Errorf("line %d %q: got %d, want %d", i+1, line, got, want[i])
+		}
+	}
+	if sc.Fence() != "```" {
+		t.Errorf("expected the last block to be open, got fence %q", sc.Fence())
+	}
+
+	if got := FenceMarker(strings.TrimSpace("  ~~~~ sh")); got != "~~~~" {
+		t.Errorf("FenceMarker: got %q", got)
+	}
+	if got := FenceMarker("``not a fence"); got != "" {
+		t.Errorf("FenceMarker: got %q", got)
+	}
+}
@@ -1,73 +1,259 @@
 package manual
 
 import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+
+	"go.yaml.in/yaml/v3"
 )
 
 // Section represents a parsed .pm/ markdown document.
 type Section struct {
-	Name  string   // filename without .md extension
-	Group string   // "core" or "custom"
-	Title string   // from frontmatter "title:" field
-	Tags  []string // from frontmatter "tags:" field
-	Body  string   // content after frontmatter
+	Name        string         // filename without .md extension
+	Group       string         // "core" or "custom"
+	Layer       string         // name of the .pm/ layer the section was loaded from
+	Title       string         // from frontmatter "title:" field
+	Description string         // from frontmatter "description:" field
+	Tags        []string       // from frontmatter "tags:" field
+	Aliases     []string       // from frontmatter "aliases:" field; alternative names for pm open
+	Params      []Param        // from frontmatter "params:" field; see Param
+	Meta        map[string]any // any other frontmatter keys, e.g. owner or severity
+	Body        string         // content after frontmatter
+}
+
+// Field returns a frontmatter value by key, formatted for display.
+// The built-in keys title, description, tags and aliases are looked up first, then Meta.
+// Keys are matched case-insensitively, an exact match in Meta winning.
+// Lists are joined with ", ".
+func (s Section) Field(key string) (string, bool) {
+	switch strings.ToLower(key) {
+	case "title":
+		return s.Title, s.Title != ""
+	case "description":
+		return s.Description, s.Description != ""
+	case "tags":
+		return strings.Join(s.Tags, ", "), len(s.Tags) > 0
+	case "aliases":
+		return strings.Join(s.Aliases, ", "), len(s.Aliases) > 0
+	}
+
+	v, ok := s.Meta[key]
+	if !ok {
+		var keys []string
+		for k := range s.Meta {
+			if strings.EqualFold(k, key) {
+				keys = append(keys, k)
+			}
+		}
+		if len(keys) > 0 {
+			slices.Sort(keys) // pick the same key every time
+			v, ok = s.Meta[keys[0]], true
+		}
+	}
+	if !ok || v == nil {
+		return "", false
+	}
+	return formatValue(v), true
+}
+
+// formatValue renders a decoded YAML value as a single line of text.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = formatValue(item)
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
 }
 
+// FrontmatterError reports malformed YAML frontmatter.
+type FrontmatterError struct {
+	Line int // 1-based line number within the file
+	Msg  string
+}
+
+func (e *FrontmatterError) Error() string {
+	return fmt.Sprintf("frontmatter line %d: %s", e.Line, e.Msg)
+}
+
+// yamlErrLine extracts the line number from yaml syntax errors ("yaml: line 3: ...").
+var yamlErrLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
 // ParseSection parses raw markdown content into a Section.
-// Frontmatter is delimited by "---" lines. Supported keys: title, description, tags.
-func ParseSection(name, group, raw string) Section {
+// Frontmatter is a YAML document delimited by "---" lines. The keys title, description,
+// tags, aliases and params fill the matching fields; any other keys are kept in Meta.
+// tags and aliases may be a YAML list or a comma-separated string ("tags: deploy, release").
+// Frontmatter of "key: value" lines that is not valid YAML is read as in earlier
+// versions (see legacyFrontmatter). Other malformed frontmatter is reported as a
+// *FrontmatterError; content without a closing "---" is treated as having no
+// frontmatter.
+func ParseSection(name, group, raw string) (Section, error) {
 	s := Section{
 		Name:  name,
 		Group: group,
 	}
 
 	lines := strings.Split(raw, "\n")
-	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+	n := FrontmatterLines(lines)
+	if n == 0 {
 		s.Body = raw
-		return s
+		return s, nil
 	}
 
-	// Find closing ---
-	closeIdx := -1
-	for i := 1; i < len(lines); i++ {
-		if strings.TrimSpace(lines[i]) == "---" {
-			closeIdx = i
-			break
-		}
+	if err := parseFrontmatter(&s, strings.Join(lines[1:n-1], "\n")); err != nil {
+		return Section{}, err
 	}
 
-	if closeIdx == -1 {
-		s.Body = raw
-		return s
+	// Body is everything after closing ---
+	if n < len(lines) {
+		s.Body = strings.Join(lines[n:], "\n")
+		s.Body = strings.TrimLeft(s.Body, "\n")
 	}
 
-	// Parse frontmatter key: value pairs
-	for _, line := range lines[1:closeIdx] {
-		idx := strings.Index(line, ":")
-		if idx == -1 {
-			continue
+	return s, nil
+}
+
+// parseFrontmatter decodes the YAML between the "---" delimiters into s.
+// Line numbers in errors are relative to the file, where the YAML starts on line 2.
+func parseFrontmatter(s *Section, src string) error {
+	const offset = 1 // the opening "---"
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(src), &doc); err != nil {
+		if legacy, ok := legacyFrontmatter(src); ok {
+			doc.Content = []*yaml.Node{legacy}
+		} else if m := yamlErrLine.FindStringSubmatch(err.Error()); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			return &FrontmatterError{Line: line + offset, Msg: yamlHint(m[2])}
+		} else {
+			return &FrontmatterError{Line: 1 + offset, Msg: yamlHint(strings.TrimPrefix(err.Error(), "yaml: "))}
 		}
-		key := strings.TrimSpace(line[:idx])
-		val := strings.TrimSpace(line[idx+1:])
+	}
 
-		switch strings.ToLower(key) {
+	if len(doc.Content) == 0 {
+		return nil // empty frontmatter
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return &FrontmatterError{Line: root.Line + offset, Msg: "expected key: value pairs"}
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, val := root.Content[i], root.Content[i+1]
+
+		switch strings.ToLower(key.Value) {
 		case "title":
-			s.Title = val
+			if val.Kind != yaml.ScalarNode {
+				return &FrontmatterError{Line: val.Line + offset, Msg: "title must be a string"}
+			}
+			s.Title = val.Value
+		case "description":
+			if val.Kind != yaml.ScalarNode {
+				return &FrontmatterError{Line: val.Line + offset, Msg: "description must be a string"}
+			}
+			s.Description = strings.TrimSpace(val.Value)
 		case "tags":
-			for _, t := range strings.Split(val, ",") {
-				t = strings.TrimSpace(t)
-				if t != "" {
-					s.Tags = append(s.Tags, t)
-				}
+			tags, err := decodeList("tags", val)
+			if err != nil {
+				return &FrontmatterError{Line: val.Line + offset, Msg: err.Error()}
+			}
+			s.Tags = tags
+		case "aliases":
+			aliases, err := decodeList("aliases", val)
+			if err != nil {
+				return &FrontmatterError{Line: val.Line + offset, Msg: err.Error()}
 			}
+			s.Aliases = aliases
+		case "params":
+			params, err := decodeParams(val)
+			if err != nil {
+				return &FrontmatterError{Line: val.Line + offset, Msg: err.Error()}
+			}
+			s.Params = params
+		default:
+			var v any
+			if err := val.Decode(&v); err != nil {
+				return &FrontmatterError{Line: val.Line + offset, Msg: err.Error()}
+			}
+			if s.Meta == nil {
+				s.Meta = make(map[string]any)
+			}
+			s.Meta[key.Value] = v
 		}
 	}
+	return nil
+}
 
-	// Body is everything after closing ---
-	if closeIdx+1 < len(lines) {
-		s.Body = strings.Join(lines[closeIdx+1:], "\n")
-		s.Body = strings.TrimLeft(s.Body, "\n")
+// legacyLine matches a line of the "key: value" frontmatter pm accepted before
+// it was parsed as YAML. Values starting like a YAML list or mapping are not
+// legacy ones.
+var legacyLine = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_-]*):(?:[ \t]+([^ \t\[{].*?))?[ \t]*$`)
+
+// legacyFrontmatter parses frontmatter that is not valid YAML but consists of
+// "key: value" lines only, as pm read it before, e.g. "title: Fix: restart db"
+// with an unquoted colon. It returns a mapping of the values as strings,
+// reporting false if a line does not have that form.
+func legacyFrontmatter(src string) (*yaml.Node, bool) {
+	root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: 1}
+	for i, line := range strings.Split(src, "\n") {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		m := legacyLine.FindStringSubmatch(line)
+		if m == nil {
+			return nil, false
+		}
+		key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: m[1], Line: i + 1}
+		val := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: m[2], Line: i + 1}
+		if m[2] == "" {
+			val.Tag = "!!null"
+		}
+		root.Content = append(root.Content, key, val)
+	}
+	return root, true
+}
+
+// yamlHint adds a hint on fixing a yaml error message for the common mistakes.
+func yamlHint(msg string) string {
+	if strings.Contains(msg, "mapping values are not allowed") {
+		return msg + ` (quote values containing ": ")`
 	}
+	return msg
+}
 
-	return s
+// decodeList decodes a list-valued key such as tags. It accepts a YAML list
+// of strings or the legacy comma-separated string form.
+func decodeList(key string, n *yaml.Node) ([]string, error) {
+	var raw []string
+	switch {
+	case n.Tag == "!!null":
+		return nil, nil
+	case n.Kind == yaml.ScalarNode:
+		raw = strings.Split(n.Value, ",")
+	case n.Kind == yaml.SequenceNode:
+		for _, item := range n.Content {
+			if item.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("%s must be a list of strings", key)
+			}
+			raw = append(raw, item.Value)
+		}
+	default:
+		return nil, fmt.Errorf("%s must be a list or comma-separated string", key)
+	}
+
+	var items []string
+	for _, t := range raw {
+		t = strings.TrimSpace(t)
+		if t != "" {
+			items = append(items, t)
+		}
+	}
+	return items, nil
 }
@@ -1,6 +1,7 @@
 package manual
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -15,7 +16,10 @@ tags: deploy, release, ci
 
 Steps here.`
 
-	s := ParseSection("deploy", "core", raw)
+	s, err := ParseSection("deploy", "core", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if s.Name != "deploy" {
 		t.Errorf("expected name 'deploy', got %q", s.Name)
@@ -26,6 +30,9 @@ Steps here.`
 	if s.Title != "Deployment Guide" {
 		t.Errorf("expected title 'Deployment Guide', got %q", s.Title)
 	}
+	if s.Description != "How to deploy" {
+		t.Errorf("expected description 'How to deploy', got %q", s.Description)
+	}
 	if len(s.Tags) != 3 {
 		t.Fatalf("expected 3 tags, got %d: %v", len(s.Tags), s.Tags)
 	}
@@ -40,7 +47,10 @@ Steps here.`
 func TestParseSection_NoFrontmatter(t *testing.T) {
 	raw := "# Just a document\n\nSome content."
 
-	s := ParseSection("readme", "custom", raw)
+	s, err := ParseSection("readme", "custom", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if s.Title != "" {
 		t.Errorf("expected empty title, got %q", s.Title)
@@ -53,7 +63,10 @@ func TestParseSection_NoFrontmatter(t *testing.T) {
 func TestParseSection_EmptyFrontmatter(t *testing.T) {
 	raw := "---\n---\n\nContent after empty frontmatter."
 
-	s := ParseSection("test", "core", raw)
+	s, err := ParseSection("test", "core", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if s.Title != "" {
 		t.Errorf("expected empty title, got %q", s.Title)
@@ -66,7 +79,10 @@ func TestParseSection_EmptyFrontmatter(t *testing.T) {
 func TestParseSection_UnclosedFrontmatter(t *testing.T) {
 	raw := "---\ntitle: Unclosed\nNo closing delimiter"
 
-	s := ParseSection("test", "core", raw)
+	s, err := ParseSection("test", "core", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	// Should treat entire content as body since frontmatter is unclosed
 	if s.Title != "" {
@@ -80,9 +96,154 @@ func TestParseSection_UnclosedFrontmatter(t *testing.T) {
 func TestParseSection_SingleTag(t *testing.T) {
 	raw := "---\ntags: solo\n---\nBody"
 
-	s := ParseSection("test", "core", raw)
+	s, err := ParseSection("test", "core", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if len(s.Tags) != 1 || s.Tags[0] != "solo" {
 		t.Errorf("expected single tag 'solo', got %v", s.Tags)
 	}
 }
+
+func TestParseSection_YAMLTagList(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"flow list", "---\ntags: [deploy, release]\n---\nBody"},
+		{"block list", "---\ntags:\n  - deploy\n  - release\n---\nBody"},
+		{"comma string", "---\ntags: deploy, release\n---\nBody"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseSection("test", "core", tt.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(s.Tags) != 2 || s.Tags[0] != "deploy" || s.Tags[1] != "release" {
+				t.Errorf("unexpected tags: %v", s.Tags)
+			}
+		})
+	}
+}
+
+func TestParseSection_Aliases(t *testing.T) {
+	s, err := ParseSection("troubleshoot", "core", "---\naliases: [debug, trbl]\nowner: sre\n---\nBody")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Aliases) != 2 || s.Aliases[0] != "debug" || s.Aliases[1] != "trbl" {
+		t.Errorf("unexpected aliases: %v", s.Aliases)
+	}
+	if _, ok := s.Meta["aliases"]; ok {
+		t.Error("aliases should not be kept in Meta")
+	}
+
+	if _, err := ParseSection("x", "core", "---\naliases: {a: b}\n---\n"); err == nil {
+		t.Error("expected error for non-list aliases")
+	}
+}
+
+func TestParseSection_QuotedAndMultilineValues(t *testing.T) {
+	raw := "---\ntitle: \"Backup: nightly\"\ndescription: |\n  line one\n  line two\n---\nBody"
+
+	s, err := ParseSection("test", "core", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Title != "Backup: nightly" {
+		t.Errorf("expected quoted title with colon, got %q", s.Title)
+	}
+	if s.Body != "Body" {
+		t.Errorf("unexpected body: %q", s.Body)
+	}
+}
+
+func TestParseSection_MalformedFrontmatter(t *testing.T) {
+	raw := "---\ntitle: ok\ntags: [unclosed\n---\nBody"
+
+	_, err := ParseSection("test", "core", raw)
+	if err == nil {
+		t.Fatal("expected error for malformed frontmatter")
+	}
+	fe, ok := err.(*FrontmatterError)
+	if !ok {
+		t.Fatalf("expected *FrontmatterError, got %T: %v", err, err)
+	}
+	if fe.Line < 2 || fe.Line > 4 {
+		t.Errorf("expected line within frontmatter, got %d", fe.Line)
+	}
+}
+
+func TestParseSection_LegacyFrontmatter(t *testing.T) {
+	raw := "---\ntitle: Fix: restart db\ndescription: When: the db hangs\ntags: db, ops\nowner: dba\n---\nBody"
+
+	s, err := ParseSection("restart", "core", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Title != "Fix: restart db" || s.Description != "When: the db hangs" {
+		t.Errorf("got title %q, description %q", s.Title, s.Description)
+	}
+	if len(s.Tags) != 2 || s.Tags[1] != "ops" || s.Meta["owner"] != "dba" || s.Body != "Body" {
+		t.Errorf("unexpected section %+v", s)
+	}
+
+	// Anything beyond key: value lines must be valid YAML.
+	_, err = ParseSection("x", "core", "---\ntitle: a: b\ntags: [db]\n---\nBody")
+	fe, ok := err.(*FrontmatterError)
+	if !ok || !strings.Contains(fe.Msg, "quote values") {
+		t.Errorf("expected a hint to quote the value, got %v", err)
+	}
+}
+
+func TestParseSection_FrontmatterNotMapping(t *testing.T) {
+	raw := "---\n- just\n- a list\n---\nBody"
+
+	_, err := ParseSection("test", "core", raw)
+	fe, ok := err.(*FrontmatterError)
+	if !ok {
+		t.Fatalf("expected *FrontmatterError, got %v", err)
+	}
+	if fe.Line != 2 {
+		t.Errorf("expected line 2, got %d", fe.Line)
+	}
+}
+
+func TestParseSection_Meta(t *testing.T) {
+	raw := "---\ntitle: Deploy\nowner: platform-team\nseverity: 2\nservices: [api, worker]\n---\nBody"
+
+	s, err := ParseSection("deploy", "core", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Meta["owner"] != "platform-team" {
+		t.Errorf("expected owner in Meta, got %v", s.Meta["owner"])
+	}
+	if _, ok := s.Meta["title"]; ok {
+		t.Error("title should not be duplicated in Meta")
+	}
+
+	tests := []struct {
+		key  string
+		want string
+		ok   bool
+	}{
+		{"owner", "platform-team", true},
+		{"Owner", "platform-team", true},
+		{"SEVERITY", "2", true},
+		{"Title", "Deploy", true},
+		{"severity", "2", true},
+		{"services", "api, worker", true},
+		{"title", "Deploy", true},
+		{"missing", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := s.Field(tt.key)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Field(%q) = %q, %v; want %q, %v", tt.key, got, ok, tt.want, tt.ok)
+		}
+	}
+}
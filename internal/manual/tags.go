@@ -0,0 +1,83 @@
+package manual
+
+import (
+	"sort"
+	"strings"
+)
+
+// TagCount is a tag and the number of sections carrying it.
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+// CountTags tallies tags across sections, case-insensitively.
+// The result is sorted by count (descending), then by tag.
+// The spelling of the first occurrence of each tag is kept.
+func CountTags(sections []Section) []TagCount {
+	index := make(map[string]int)
+	var counts []TagCount
+	for _, s := range sections {
+		seen := make(map[string]bool)
+		for _, t := range s.Tags {
+			key := strings.ToLower(t)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if i, ok := index[key]; ok {
+				counts[i].Count++
+				continue
+			}
+			index[key] = len(counts)
+			counts = append(counts, TagCount{Tag: t, Count: 1})
+		}
+	}
+
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Tag < counts[j].Tag
+	})
+	return counts
+}
+
+// HasTags reports whether the section carries the given tags: all of them, or
+// at least one when matchAny is set. Comparison is case-insensitive.
+// An empty tag list always matches.
+func (s Section) HasTags(tags []string, matchAny bool) bool {
+	if len(tags) == 0 {
+		return true
+	}
+
+	have := make(map[string]bool, len(s.Tags))
+	for _, t := range s.Tags {
+		have[strings.ToLower(t)] = true
+	}
+
+	for _, t := range tags {
+		ok := have[strings.ToLower(t)]
+		if matchAny && ok {
+			return true
+		}
+		if !matchAny && !ok {
+			return false
+		}
+	}
+	return !matchAny
+}
+
+// FilterByTags returns the sections for which HasTags(tags, matchAny) is true.
+func FilterByTags(sections []Section, tags []string, matchAny bool) []Section {
+	if len(tags) == 0 {
+		return sections
+	}
+	var out []Section
+	for _, s := range sections {
+		if s.HasTags(tags, matchAny) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,47 @@
+package manual
+
+import "testing"
+
+func TestCountTags(t *testing.T) {
+	sections := []Section{
+		{Name: "deploy", Tags: []string{"deploy", "release"}},
+		{Name: "troubleshoot", Tags: []string{"incident", "debug"}},
+		{Name: "monitoring", Tags: []string{"Incident", "alerts", "incident"}},
+	}
+
+	counts := CountTags(sections)
+	if len(counts) != 5 {
+		t.Fatalf("expected 5 distinct tags, got %d: %v", len(counts), counts)
+	}
+	if counts[0].Tag != "incident" || counts[0].Count != 2 {
+		t.Errorf("expected incident x2 first, got %+v", counts[0])
+	}
+	if counts[1].Tag != "alerts" {
+		t.Errorf("expected ties sorted by name, got %+v", counts[1])
+	}
+}
+
+func TestHasTags(t *testing.T) {
+	s := Section{Tags: []string{"incident", "debug"}}
+
+	tests := []struct {
+		name     string
+		tags     []string
+		matchAny bool
+		want     bool
+	}{
+		{"no filter", nil, false, true},
+		{"all present", []string{"incident", "DEBUG"}, false, true},
+		{"all missing one", []string{"incident", "deploy"}, false, false},
+		{"any present", []string{"deploy", "incident"}, true, true},
+		{"any missing", []string{"deploy", "release"}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.HasTags(tt.tags, tt.matchAny); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package manual
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Task is a task-list item ("- [ ] Rollback plan prepared") in a section body.
+type Task struct {
+	Text    string   // item text after the checkbox
+	Checked bool     // ticked in the markdown itself ("- [x]")
+	Line    int      // 1-based line number within the parsed text
+	Path    []string // texts of the enclosing headings, outermost first
+	Key     string   // identifies the item across edits elsewhere in the section (see ParseTasks)
+}
+
+var taskRe = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\]\s+(.*?)\s*$`)
+
+// ParseTasks returns the task-list items in a markdown body, in order. Items
+// inside fenced code blocks are ignored. An item's Key is its heading path and
+// text, e.g. "Deploy > Pre-deployment Checklist > All tests passing", with a
+// " (2)", " (3)", ... suffix for repeated items, so that progress saved by key
+// survives lines being added or removed around the item.
+func ParseTasks(body string) []Task {
+	headings := ParseHeadings(body)
+	keys := make(map[string]int)
+
+	var tasks []Task
+	var sc Scanner
+	for i, line := range strings.Split(body, "\n") {
+		if sc.Scan(line) != TextLine {
+			continue
+		}
+
+		m := taskRe.FindStringSubmatch(line)
+		if m == nil || m[2] == "" {
+			continue
+		}
+		t := Task{Text: m[2], Checked: m[1] != " ", Line: i + 1, Path: HeadingPath(headings, i+1)}
+		t.Key = strings.Join(append(append([]string(nil), t.Path...), t.Text), " > ")
+		if n := keys[t.Key]; n > 0 {
+			keys[t.Key]++
+			t.Key = fmt.Sprintf("%s (%d)", t.Key, n+1)
+		} else {
+			keys[t.Key] = 1
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks
+}
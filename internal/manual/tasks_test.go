@@ -0,0 +1,43 @@
+package manual
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTasks(t *testing.T) {
+	body := strings.Join([]string{
+		"# Deploy",
+		"",
+		"## Checklist",
+		"",
+		"- [ ] All tests passing",
+		"- [x] Rollback plan prepared",
+		"  * [X] Nested item",
+		"1. [ ] Numbered item",
+		"- [ ] All tests passing",
+		"- plain item",
+		"- [ ]",
+		"",
+		"```markdown",
+		"- [ ] not a task",
+		"```",
+		"",
+		"## After",
+		"",
+		"- [ ] All tests passing  ",
+	}, "\n")
+
+	want := []Task{
+		{Text: "All tests passing", Line: 5, Path: []string{"Deploy", "Checklist"}, Key: "Deploy > Checklist > All tests passing"},
+		{Text: "Rollback plan prepared", Checked: true, Line: 6, Path: []string{"Deploy", "Checklist"}, Key: "Deploy > Checklist > Rollback plan prepared"},
+		{Text: "Nested item", Checked: true, Line: 7, Path: []string{"Deploy", "Checklist"}, Key: "Deploy > Checklist > Nested item"},
+		{Text: "Numbered item", Line: 8, Path: []string{"Deploy", "Checklist"}, Key: "Deploy > Checklist > Numbered item"},
+		{Text: "All tests passing", Line: 9, Path: []string{"Deploy", "Checklist"}, Key: "Deploy > Checklist > All tests passing (2)"},
+		{Text: "All tests passing", Line: 19, Path: []string{"Deploy", "After"}, Key: "Deploy > After > All tests passing"},
+	}
+	if got := ParseTasks(body); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
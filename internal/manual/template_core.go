@@ -145,7 +145,7 @@ tags: backup, recovery, disaster
 
 ### Full Database Restore
 
-` + "```bash" + `
+` + "```bash {destructive}" + `
 # TODO: Add restore commands
 ` + "```" + `
 
@@ -180,7 +180,7 @@ tags: maintenance, cron, cleanup
 
 ## Cache Management
 
-` + "```bash" + `
+` + "```bash {destructive}" + `
 # TODO: Add cache flush / warm-up commands
 ` + "```" + `
 `
@@ -33,7 +33,11 @@ func TestDefaultTemplates_HaveFrontmatter(t *testing.T) {
 
 func TestDefaultTemplates_Parseable(t *testing.T) {
 	for name, tmpl := range DefaultTemplates {
-		s := ParseSection(name, "core", tmpl)
+		s, err := ParseSection(name, "core", tmpl)
+		if err != nil {
+			t.Errorf("template %q: %v", name, err)
+			continue
+		}
 		if s.Title == "" {
 			t.Errorf("template %q should have a title after parsing", name)
 		}